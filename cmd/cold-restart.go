@@ -32,7 +32,7 @@ during pod start and subsequent cold restarts.`,
 	RunE: func(_ *cobra.Command, _ []string) error {
 		ctx := goctx.TODO()
 
-		initParams, err := pkg.PopulateInitParams(ctx)
+		initParams, err := pkg.PopulateInitParams(ctx, &initOptions)
 		if err != nil {
 			return err
 		}
@@ -37,7 +37,7 @@ restart asd process in server and updating CR status.`,
 	RunE: func(_ *cobra.Command, _ []string) error {
 		ctx := goctx.TODO()
 
-		initParams, err := pkg.PopulateInitParams(ctx)
+		initParams, err := pkg.PopulateInitParams(ctx, &initOptions)
 		if err != nil {
 			return err
 		}
@@ -50,4 +50,12 @@ func init() {
 	rootCmd.AddCommand(quickRestart)
 	quickRestart.Flags().StringVar(&cmName, "cm-name", "", "configmap name")
 	quickRestart.Flags().StringVar(&cmNamespace, "cm-namespace", "", "configmap namespace")
+	quickRestart.Flags().StringVar(&initOptions.ConfigMap.ExpectedConfHash, "expected-conf-hash", "",
+		"aerospikeConfHash the configmap must hold before it is used, from the AerospikeCluster spec if empty")
+	quickRestart.Flags().DurationVar(&initOptions.ConfigMap.WaitTimeout, "cm-wait-timeout", initOptions.ConfigMap.WaitTimeout,
+		"time allowed for the configmap to reach the expected conf hash")
+	quickRestart.Flags().DurationVar(&initOptions.Restart.ASDStartTimeout, "asd-start-timeout",
+		initOptions.Restart.ASDStartTimeout, "time allowed for the restarted Aerospike server to become healthy")
+	quickRestart.Flags().BoolVar(&initOptions.Restart.DynamicConfig, "dynamic-config",
+		initOptions.Restart.DynamicConfig, "apply dynamic config changes with set-config instead of restarting")
 }
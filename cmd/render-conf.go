@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The aerospike-operator Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	goctx "context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aerospike/aerospike-kubernetes-init/pkg"
+)
+
+var (
+	offlineObjects pkg.OfflineObjects
+	templateFile   string
+	peersFile      string
+)
+
+// renderConf represents the render-conf command
+var renderConf = &cobra.Command{
+	Use:   "render-conf",
+	Short: "render aerospike.conf offline",
+	Long: `This command renders the final aerospike.conf and the access endpoints
+for a pod from local AerospikeCluster, Pod and Node manifests and a template,
+without talking to a k8s API server.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx := goctx.TODO()
+
+		if templateFile == "" {
+			return fmt.Errorf("aerospike.conf template required as an argument")
+		}
+
+		template, err := os.ReadFile(templateFile)
+		if err != nil {
+			return err
+		}
+
+		var peersData []byte
+
+		if peersFile != "" {
+			if peersData, err = os.ReadFile(peersFile); err != nil {
+				return err
+			}
+		}
+
+		initParams, err := pkg.PopulateOfflineInitParams(ctx, &initOptions, &offlineObjects)
+		if err != nil {
+			return err
+		}
+
+		confString, err := initParams.RenderAerospikeConf(template, peersData)
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintln(out, confString)
+
+		endpoints := initParams.Endpoints()
+		addressTypes := make([]string, 0, len(endpoints))
+
+		for addressType := range endpoints {
+			addressTypes = append(addressTypes, addressType)
+		}
+
+		sort.Strings(addressTypes)
+
+		for _, addressType := range addressTypes {
+			fmt.Fprintf(out, "# %s-endpoints: %v\n", addressType, endpoints[addressType])
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renderConf)
+	renderConf.Flags().StringVar(&offlineObjects.ClusterFile, "cluster", "", "AerospikeCluster manifest file")
+	renderConf.Flags().StringVar(&offlineObjects.PodFile, "pod", "", "pod manifest file")
+	renderConf.Flags().StringVar(&offlineObjects.NodeFile, "node", "", "node manifest file")
+	renderConf.Flags().StringVar(&offlineObjects.ServiceFile, "service", "", "per-pod service manifest file")
+	renderConf.Flags().StringVar(&templateFile, "template", "", "aerospike.template.conf file")
+	renderConf.Flags().StringVar(&peersFile, "peers", "", "peers file")
+}
@@ -16,11 +16,16 @@ limitations under the License.
 package cmd
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
+
+	"github.com/aerospike/aerospike-kubernetes-init/pkg"
 )
 
+var initOptions = pkg.DefaultOptions()
+
 var rootCmd = &cobra.Command{
 	Use:   "akoinit",
 	Short: "A library for init container",
@@ -28,8 +33,53 @@ var rootCmd = &cobra.Command{
 quick-restart and cold-restart `,
 }
 
+func init() {
+	flags := rootCmd.PersistentFlags()
+	paths := &initOptions.Paths
+
+	flags.StringVar(&paths.ConfigVolume, "config-volume", paths.ConfigVolume,
+		"volume shared with the server container holding aerospike.conf")
+	flags.StringVar(&paths.ConfigsDir, "configs-dir", paths.ConfigsDir, "mounted operator configmap directory")
+	flags.StringVar(&paths.BinDir, "bin-dir", paths.BinDir, "directory holding the akoinit binary")
+	flags.StringVar(&paths.FileSystemVolumesDir, "filesystem-volumes-dir", paths.FileSystemVolumesDir,
+		"directory where filesystem volumes are mounted")
+	flags.StringVar(&paths.BlockVolumesDir, "block-volumes-dir", paths.BlockVolumesDir,
+		"directory where block volumes are mounted")
+	flags.StringVar(&paths.InitCmdline, "init-cmdline", paths.InitCmdline,
+		"cmdline file of the server container init process")
+	flags.StringVar(&paths.TerminationLog, "termination-log", paths.TerminationLog,
+		"init container termination message file the cold-restart JSON report is written to, if it exists")
+	flags.StringVar(&initOptions.ReportFile, "report-file", "", "extra file the JSON init report is written to")
+	flags.DurationVar(&initOptions.Network.LoadBalancerWaitTimeout, "lb-wait-timeout",
+		initOptions.Network.LoadBalancerWaitTimeout, "time allowed for the pod LoadBalancer service to get an ingress")
+	flags.DurationVar(&initOptions.Network.CustomNetworkWaitTimeout, "custom-network-wait-timeout",
+		initOptions.Network.CustomNetworkWaitTimeout, "time allowed for the pod CNI network status to list custom network IPs")
+	flags.StringVar(&initOptions.Network.ClusterDomain, "cluster-domain", initOptions.Network.ClusterDomain,
+		"DNS domain of the k8s cluster used by the default DNS name template")
+	flags.StringSliceVar(&initOptions.RedactKeys, "redact-keys", nil,
+		"extra aerospike.conf parameters to mask in logs and the pod annotation")
+	flags.StringVar(&initOptions.ConfStorage, "conf-storage", initOptions.ConfStorage,
+		"where the rendered aerospike.conf is published: auto, annotation, gzip, configmap or hash")
+
+	initOptions.Report = pkg.NewInitReport()
+}
+
 func Execute() {
-	err := rootCmd.Execute()
+	cmd, err := rootCmd.ExecuteC()
+
+	initOptions.Report.Finish(cmd.Name(), err)
+
+	// Only cold-restart runs as the init container, the other commands are exec'ed in the server container
+	// and must not overwrite its termination message.
+	terminationLog := ""
+	if cmd.Name() == "cold-restart" {
+		terminationLog = initOptions.Paths.TerminationLog
+	}
+
+	if writeErr := initOptions.Report.Write(terminationLog, initOptions.ReportFile); writeErr != nil {
+		fmt.Fprintln(os.Stderr, writeErr)
+	}
+
 	if err != nil {
 		os.Exit(1)
 	}
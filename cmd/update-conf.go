@@ -31,7 +31,7 @@ var confUpdate = &cobra.Command{
 	RunE: func(_ *cobra.Command, _ []string) error {
 		ctx := goctx.TODO()
 
-		initParams, err := pkg.PopulateInitParams(ctx)
+		initParams, err := pkg.PopulateInitParams(ctx, &initOptions)
 		if err != nil {
 			return err
 		}
@@ -44,4 +44,8 @@ func init() {
 	rootCmd.AddCommand(confUpdate)
 	confUpdate.Flags().StringVar(&cmName, "cm-name", "", "configmap name")
 	confUpdate.Flags().StringVar(&cmNamespace, "cm-namespace", "", "configmap namespace")
+	confUpdate.Flags().StringVar(&initOptions.ConfigMap.ExpectedConfHash, "expected-conf-hash", "",
+		"aerospikeConfHash the configmap must hold before it is used, from the AerospikeCluster spec if empty")
+	confUpdate.Flags().DurationVar(&initOptions.ConfigMap.WaitTimeout, "cm-wait-timeout", initOptions.ConfigMap.WaitTimeout,
+		"time allowed for the configmap to reach the expected conf hash")
 }
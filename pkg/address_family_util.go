@@ -0,0 +1,116 @@
+package pkg
+
+import (
+	"fmt"
+	"net"
+)
+
+// addressFamily is the IP family of the addresses advertised for an access type.
+type addressFamily string
+
+const (
+	addressFamilyIPv4      addressFamily = "IPv4"
+	addressFamilyIPv6      addressFamily = "IPv6"
+	addressFamilyDualStack addressFamily = "DualStack"
+
+	// addressFamilyAnnotationSuffix is appended to the access type, e.g. aerospike.com/access-address-family,
+	// to form the AerospikeCluster annotation selecting the address family of that access type.
+	addressFamilyAnnotationSuffix = "-address-family"
+)
+
+// ipFamilies holds an address of each IP family, empty if the family is not available.
+type ipFamilies struct {
+	ipv4 string
+	ipv6 string
+}
+
+// set stores the address in the slot of its family, invalid addresses are ignored.
+func (ips *ipFamilies) set(address string) {
+	ip := net.ParseIP(address)
+
+	switch {
+	case ip == nil:
+		return
+	case ip.To4() != nil:
+		ips.ipv4 = address
+	default:
+		ips.ipv6 = address
+	}
+}
+
+// get returns the address of the given family, or of the family of the fallback address if the family is not set.
+// The fallback address is returned when no address of the family is available.
+func (ips *ipFamilies) get(family addressFamily, fallback string) string {
+	if family == "" {
+		family = ipFamilyOf(fallback)
+	}
+
+	address := ips.ipv4
+	if family == addressFamilyIPv6 {
+		address = ips.ipv6
+	}
+
+	if address == "" {
+		return fallback
+	}
+
+	return address
+}
+
+// addresses returns the addresses to advertise for the given family. By default only the fallback address is
+// advertised, which is the primary address of the pod or the host.
+func (ips *ipFamilies) addresses(family addressFamily, fallback string) []string {
+	switch family {
+	case addressFamilyIPv4, addressFamilyIPv6:
+		return []string{ips.get(family, fallback)}
+
+	case addressFamilyDualStack:
+		var addresses []string
+
+		for _, address := range []string{ips.ipv4, ips.ipv6} {
+			if address != "" {
+				addresses = append(addresses, address)
+			}
+		}
+
+		if len(addresses) != 0 {
+			return addresses
+		}
+	}
+
+	return []string{fallback}
+}
+
+// ipFamilyOf returns the family of the given address, IPv4 if it is not a valid address.
+func ipFamilyOf(address string) addressFamily {
+	if ip := net.ParseIP(address); ip != nil && ip.To4() == nil {
+		return addressFamilyIPv6
+	}
+
+	return addressFamilyIPv4
+}
+
+// getAddressFamilies reads the address family of each access type from the AerospikeCluster annotations.
+// Access types without an annotation are not set and advertise the primary address of the pod or the host.
+func (initp *InitParams) getAddressFamilies() (map[string]addressFamily, error) {
+	families := make(map[string]addressFamily)
+
+	for _, addressType := range []string{access, alternateAccess, tlsAccess, tlsAlternateAccess} {
+		annotation := "aerospike.com/" + addressType + addressFamilyAnnotationSuffix
+
+		value, ok := initp.aeroCluster.Annotations[annotation]
+		if !ok {
+			continue
+		}
+
+		switch family := addressFamily(value); family {
+		case addressFamilyIPv4, addressFamilyIPv6, addressFamilyDualStack:
+			families[addressType] = family
+		default:
+			return nil, fmt.Errorf("invalid address family %q in annotation %s, expected %s, %s or %s",
+				value, annotation, addressFamilyIPv4, addressFamilyIPv6, addressFamilyDualStack)
+		}
+	}
+
+	return families, nil
+}
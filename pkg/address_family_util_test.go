@@ -0,0 +1,178 @@
+package pkg
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	asdbv1 "github.com/aerospike/aerospike-kubernetes-operator/v4/api/v1"
+)
+
+func TestRenderAerospikeConfAddressFamilies(t *testing.T) {
+	const template = `service {
+    node-id ENV_NODE_ID
+}
+network {
+    service {
+        port 3000
+        access-address <access-address>
+        access-port 3000
+        alternate-access-address <alternate-access-address>
+        alternate-access-port 3000
+    }
+}
+`
+
+	dualStack := ipFamilies{ipv4: "10.0.0.1", ipv6: "fd00::1"}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		// podIP is the primary pod IP, podIPs the pod IPs of each family.
+		podIP               string
+		podIPs              ipFamilies
+		wantAccess          []string
+		wantAlternateAccess []string
+		wantErr             string
+	}{
+		{
+			name:                "primary address by default",
+			podIP:               "fd00::1",
+			podIPs:              dualStack,
+			wantAccess:          []string{"fd00::1"},
+			wantAlternateAccess: []string{"192.168.0.1"},
+		},
+		{
+			name:                "IPv4",
+			annotations:         map[string]string{"aerospike.com/access-address-family": "IPv4"},
+			podIP:               "fd00::1",
+			podIPs:              dualStack,
+			wantAccess:          []string{"10.0.0.1"},
+			wantAlternateAccess: []string{"192.168.0.1"},
+		},
+		{
+			name:                "IPv6",
+			annotations:         map[string]string{"aerospike.com/access-address-family": "IPv6"},
+			podIP:               "10.0.0.1",
+			podIPs:              dualStack,
+			wantAccess:          []string{"fd00::1"},
+			wantAlternateAccess: []string{"192.168.0.1"},
+		},
+		{
+			name:                "IPv6 on single-stack pod",
+			annotations:         map[string]string{"aerospike.com/access-address-family": "IPv6"},
+			podIP:               "10.0.0.1",
+			podIPs:              ipFamilies{ipv4: "10.0.0.1"},
+			wantAccess:          []string{"10.0.0.1"},
+			wantAlternateAccess: []string{"192.168.0.1"},
+		},
+		{
+			name: "dual-stack",
+			annotations: map[string]string{
+				"aerospike.com/access-address-family":           "DualStack",
+				"aerospike.com/alternate-access-address-family": "DualStack",
+			},
+			podIP:               "fd00::1",
+			podIPs:              dualStack,
+			wantAccess:          []string{"10.0.0.1", "fd00::1"},
+			wantAlternateAccess: []string{"192.168.0.1", "fd01::1"},
+		},
+		{
+			name:        "invalid family",
+			annotations: map[string]string{"aerospike.com/alternate-access-address-family": "IPv5"},
+			wantErr:     `invalid address family "IPv5" in annotation aerospike.com/alternate-access-address-family`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initp := &InitParams{
+				logger: logr.Discard(),
+				aeroCluster: &asdbv1.AerospikeCluster{
+					ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+				},
+				podName: "aerospike-0-0",
+				nodeID:  "0a0",
+			}
+
+			families, err := initp.getAddressFamilies()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("getAddressFamilies() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("getAddressFamilies() error = %v", err)
+			}
+
+			initp.networkInfo = &networkInfo{
+				networkPolicy: asdbv1.AerospikeNetworkPolicy{
+					AccessType:          asdbv1.AerospikeNetworkTypePod,
+					AlternateAccessType: asdbv1.AerospikeNetworkTypeHostInternal,
+				},
+				addressFamilies:   families,
+				podIP:             tt.podIP,
+				podIPs:            tt.podIPs,
+				internalIP:        "192.168.0.1",
+				internalIPs:       ipFamilies{ipv4: "192.168.0.1", ipv6: "fd01::1"},
+				servicePort:       3000,
+				mappedServicePort: 3000,
+			}
+
+			rendered, err := initp.RenderAerospikeConf([]byte(template), nil)
+			if err != nil {
+				t.Fatalf("RenderAerospikeConf() error = %v", err)
+			}
+
+			conf, err := parseAerospikeConf(rendered)
+			if err != nil {
+				t.Fatalf("failed to parse rendered conf: %v", err)
+			}
+
+			service := conf.section("network", "service")
+
+			for _, check := range []struct {
+				param string
+				want  []string
+			}{
+				{param: "access-address", want: tt.wantAccess},
+				{param: "alternate-access-address", want: tt.wantAlternateAccess},
+			} {
+				var got []string
+				for _, param := range service.params(check.param) {
+					got = append(got, param.value)
+				}
+
+				if !reflect.DeepEqual(got, check.want) {
+					t.Errorf("rendered %s = %q, want %q", check.param, got, check.want)
+				}
+			}
+
+			if got := initp.Endpoints()[access]; !reflect.DeepEqual(got, endpoints(tt.wantAccess, 3000)) {
+				t.Errorf("Endpoints()[%s] = %q, want %q", access, got, endpoints(tt.wantAccess, 3000))
+			}
+		})
+	}
+}
+
+// endpoints returns the host:port endpoints of the addresses as reported in the pod status.
+func endpoints(addresses []string, port int) []string {
+	result := make([]string, 0, len(addresses))
+
+	for _, address := range addresses {
+		if strings.Contains(address, ":") {
+			address = "[" + address + "]"
+		}
+
+		result = append(result, address+":"+strconv.Itoa(port))
+	}
+
+	return result
+}
@@ -0,0 +1,216 @@
+package pkg
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+)
+
+type confEntryKind int
+
+const (
+	confParam confEntryKind = iota
+	confSection
+	confComment
+	confBlank
+)
+
+// confIndent is the indentation used per nesting level, same as the config writer in management lib.
+const confIndent = "    "
+
+// confEntry is a node of the aerospike.conf section tree.
+// For a parameter, name is the parameter name and value is the rest of the line.
+// For a section, value holds the optional section arguments e.g. "test" for "namespace test {".
+// For a comment, value holds the complete comment line.
+type confEntry struct {
+	name     string
+	value    string
+	children []*confEntry
+	kind     confEntryKind
+}
+
+// parseAerospikeConf parses the Aerospike config grammar into a section tree.
+// The returned root entry is an unnamed section holding the top level entries.
+func parseAerospikeConf(confString string) (*confEntry, error) {
+	root := &confEntry{kind: confSection}
+	stack := []*confEntry{root}
+
+	scanner := bufio.NewScanner(strings.NewReader(confString))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+
+		line := strings.TrimSpace(scanner.Text())
+		current := stack[len(stack)-1]
+
+		// A comment may follow the braces of a section, it is dropped. Parameters keep theirs in the value.
+		content := line
+		if idx := strings.Index(line, "#"); idx > 0 {
+			content = strings.TrimSpace(line[:idx])
+		}
+
+		switch {
+		case line == "":
+			current.children = append(current.children, &confEntry{kind: confBlank})
+
+		case strings.HasPrefix(line, "#"):
+			current.children = append(current.children, &confEntry{kind: confComment, value: line})
+
+		case content == "}":
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("unexpected '}' at line %d", lineNum)
+			}
+
+			stack = stack[:len(stack)-1]
+
+		case strings.HasSuffix(content, "{"):
+			fields := strings.Fields(strings.TrimSuffix(content, "{"))
+			if len(fields) == 0 {
+				return nil, fmt.Errorf("section without name at line %d", lineNum)
+			}
+
+			section := &confEntry{
+				kind:  confSection,
+				name:  fields[0],
+				value: strings.Join(fields[1:], " "),
+			}
+
+			current.children = append(current.children, section)
+			stack = append(stack, section)
+
+		default:
+			name := strings.Fields(line)[0]
+
+			current.children = append(current.children, &confEntry{
+				kind:  confParam,
+				name:  name,
+				value: strings.TrimSpace(strings.TrimPrefix(line, name)),
+			})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(stack) != 1 {
+		return nil, fmt.Errorf("section %q is not closed", stack[len(stack)-1].name)
+	}
+
+	return root, nil
+}
+
+// String writes the section tree back in the Aerospike config grammar.
+func (e *confEntry) String() string {
+	var sb strings.Builder
+
+	for _, child := range e.children {
+		child.write(&sb, 0)
+	}
+
+	return sb.String()
+}
+
+func (e *confEntry) write(sb *strings.Builder, level int) {
+	indent := strings.Repeat(confIndent, level)
+
+	switch e.kind {
+	case confBlank:
+		sb.WriteString("\n")
+
+	case confComment:
+		sb.WriteString(indent + e.value + "\n")
+
+	case confParam:
+		sb.WriteString(indent + e.name)
+
+		if e.value != "" {
+			sb.WriteString(confIndent + e.value)
+		}
+
+		sb.WriteString("\n")
+
+	case confSection:
+		sb.WriteString(indent + e.name)
+
+		if e.value != "" {
+			sb.WriteString(" " + e.value)
+		}
+
+		sb.WriteString(" {\n")
+
+		for _, child := range e.children {
+			child.write(sb, level+1)
+		}
+
+		sb.WriteString(indent + "}\n")
+	}
+}
+
+// sections returns the child sections with the given name.
+func (e *confEntry) sections(name string) []*confEntry {
+	var sections []*confEntry
+
+	for _, child := range e.children {
+		if child.kind == confSection && child.name == name {
+			sections = append(sections, child)
+		}
+	}
+
+	return sections
+}
+
+// section returns the first section found by following the given path of section names, or nil.
+func (e *confEntry) section(path ...string) *confEntry {
+	current := e
+
+	for _, name := range path {
+		sections := current.sections(name)
+		if len(sections) == 0 {
+			return nil
+		}
+
+		current = sections[0]
+	}
+
+	return current
+}
+
+// params returns the child parameters with the given name.
+func (e *confEntry) params(name string) []*confEntry {
+	var params []*confEntry
+
+	for _, child := range e.children {
+		if child.kind == confParam && child.name == name {
+			params = append(params, child)
+		}
+	}
+
+	return params
+}
+
+// prependParam adds a parameter at the beginning of the section.
+func (e *confEntry) prependParam(name, value string) {
+	e.children = append([]*confEntry{{kind: confParam, name: name, value: value}}, e.children...)
+}
+
+// replaceParam replaces every parameter with the given name and value by one parameter per new value.
+func (e *confEntry) replaceParam(name, oldValue string, newValues []string) {
+	children := make([]*confEntry, 0, len(e.children)+len(newValues))
+
+	for _, child := range e.children {
+		if child.kind != confParam || child.name != name || child.value != oldValue {
+			children = append(children, child)
+			continue
+		}
+
+		for _, value := range newValues {
+			children = append(children, &confEntry{kind: confParam, name: name, value: value})
+		}
+	}
+
+	e.children = children
+}
@@ -0,0 +1,183 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAerospikeConf(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "round trip",
+			conf: "# Aerospike config\n" +
+				"service {\n" +
+				"    cluster-name    test\n" +
+				"}\n" +
+				"\n" +
+				"namespace test {\n" +
+				"    replication-factor    2\n" +
+				"}\n",
+			want: "# Aerospike config\n" +
+				"service {\n" +
+				"    cluster-name    test\n" +
+				"}\n" +
+				"\n" +
+				"namespace test {\n" +
+				"    replication-factor    2\n" +
+				"}\n",
+		},
+		{
+			name: "nested sections",
+			conf: "network {\n" +
+				"    service {\n" +
+				"        port    3000\n" +
+				"    }\n" +
+				"    heartbeat {\n" +
+				"        mode    mesh\n" +
+				"        mesh-seed-address-port    10.0.0.1 3002\n" +
+				"    }\n" +
+				"}\n" +
+				"namespace test {\n" +
+				"    storage-engine device {\n" +
+				"        file    /opt/aerospike/data/test.dat\n" +
+				"    }\n" +
+				"}\n",
+			want: "network {\n" +
+				"    service {\n" +
+				"        port    3000\n" +
+				"    }\n" +
+				"    heartbeat {\n" +
+				"        mode    mesh\n" +
+				"        mesh-seed-address-port    10.0.0.1 3002\n" +
+				"    }\n" +
+				"}\n" +
+				"namespace test {\n" +
+				"    storage-engine device {\n" +
+				"        file    /opt/aerospike/data/test.dat\n" +
+				"    }\n" +
+				"}\n",
+		},
+		{
+			name: "odd indentation",
+			conf: "service{\n" +
+				"\t\tcluster-name   \t test\n" +
+				"  }\n" +
+				"   namespace   test   {\n" +
+				" replication-factor 2\n" +
+				"\t}\n",
+			want: "service {\n" +
+				"    cluster-name    test\n" +
+				"}\n" +
+				"namespace test {\n" +
+				"    replication-factor    2\n" +
+				"}\n",
+		},
+		{
+			name: "trailing comments",
+			conf: "namespace test { # test namespace\n" +
+				"    default-ttl 0 # never expire\n" +
+				"} # namespace test\n" +
+				"service {\n" +
+				"}\n",
+			want: "namespace test {\n" +
+				"    default-ttl    0 # never expire\n" +
+				"}\n" +
+				"service {\n" +
+				"}\n",
+		},
+		{
+			name:    "unexpected closing brace",
+			conf:    "service {\n}\n}\n",
+			wantErr: "unexpected '}' at line 3",
+		},
+		{
+			name:    "unclosed section",
+			conf:    "network {\n    service {\n        port 3000\n    }\n",
+			wantErr: `section "network" is not closed`,
+		},
+		{
+			name:    "section without name",
+			conf:    "{\n}\n",
+			wantErr: "section without name at line 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := parseAerospikeConf(tt.conf)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseAerospikeConf() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseAerospikeConf() error = %v", err)
+			}
+
+			if got := root.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+
+			// The written config parses back to the same config.
+			reparsed, err := parseAerospikeConf(tt.want)
+			if err != nil {
+				t.Fatalf("parseAerospikeConf() of written conf error = %v", err)
+			}
+
+			if got := reparsed.String(); got != tt.want {
+				t.Errorf("String() of written conf = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfEntrySection(t *testing.T) {
+	root, err := parseAerospikeConf("network {\n" +
+		"    service {\n" +
+		"        access-address 10.0.0.1\n" +
+		"        access-address 10.0.0.2\n" +
+		"    }\n" +
+		"}\n")
+	if err != nil {
+		t.Fatalf("parseAerospikeConf() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		path       []string
+		param      string
+		wantFound  bool
+		wantParams int
+	}{
+		{name: "nested section", path: []string{"network", "service"}, param: "access-address", wantFound: true,
+			wantParams: 2},
+		{name: "parent section", path: []string{"network"}, param: "access-address", wantFound: true},
+		{name: "missing section", path: []string{"network", "heartbeat"}},
+		{name: "missing parent section", path: []string{"service"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			section := root.section(tt.path...)
+			if (section != nil) != tt.wantFound {
+				t.Fatalf("section(%v) = %v, want found %v", tt.path, section, tt.wantFound)
+			}
+
+			if section == nil {
+				return
+			}
+
+			if got := len(section.params(tt.param)); got != tt.wantParams {
+				t.Errorf("params(%s) returned %d params, want %d", tt.param, got, tt.wantParams)
+			}
+		})
+	}
+}
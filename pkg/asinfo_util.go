@@ -0,0 +1,141 @@
+package pkg
+
+import (
+	goctx "context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	aero "github.com/aerospike/aerospike-client-go/v8"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	asdbv1 "github.com/aerospike/aerospike-kubernetes-operator/v4/api/v1"
+)
+
+const (
+	asdExecutable        = "asd"
+	asdStartPollInterval = 5 * time.Second
+
+	infoCommandStatus = "status"
+	infoCommandNode   = "node"
+	infoCommandBuild  = "build"
+
+	infoRequestTimeout = 5 * time.Second
+
+	// userPasswordSecretKey is the key holding the password in the secret of an access control user.
+	userPasswordSecretKey = "password"
+)
+
+// requestInfo sends the info commands to the Aerospike server at address and returns the value
+// returned for each command. The user of the policy logs in first if set.
+func requestInfo(ctx goctx.Context, clientPolicy *aero.ClientPolicy, address string,
+	commands ...string) (map[string]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	hostName, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid info port %s: %v", portStr, err)
+	}
+
+	deadline := time.Now().Add(infoRequestTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	policy := *clientPolicy
+	policy.Timeout = time.Until(deadline)
+
+	conn, aerr := aero.NewConnection(&policy, aero.NewHost(hostName, port))
+	if aerr != nil {
+		return nil, aerr
+	}
+
+	defer conn.Close()
+
+	if aerr := conn.SetTimeout(deadline, 0); aerr != nil {
+		return nil, aerr
+	}
+
+	if aerr := conn.Login(&policy); aerr != nil {
+		return nil, fmt.Errorf("failed to login as user %s: %v", policy.User, aerr)
+	}
+
+	info, aerr := conn.RequestInfo(commands...)
+	if aerr != nil {
+		return nil, aerr
+	}
+
+	return info, nil
+}
+
+// infoClientPolicy returns the client policy of the info requests. It logs in as the operator admin user
+// if the security section is set in aerospike.conf.
+func (initp *InitParams) infoClientPolicy(ctx goctx.Context) (*aero.ClientPolicy, error) {
+	policy := aero.NewClientPolicy()
+
+	data, err := os.ReadFile(initp.paths.aerospikeConf())
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := parseAerospikeConf(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse aerospike conf: %v", err)
+	}
+
+	if root.section("security") == nil {
+		return policy, nil
+	}
+
+	password, err := initp.adminPassword(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	policy.User = asdbv1.AdminUsername
+	policy.Password = password
+
+	return policy, nil
+}
+
+// adminPassword returns the password of the operator admin user, read from the secret of the user
+// in the aerospikeAccessControl of the cluster.
+func (initp *InitParams) adminPassword(ctx goctx.Context) (string, error) {
+	accessControl := initp.aeroCluster.Spec.AerospikeAccessControl
+	if accessControl == nil {
+		return "", fmt.Errorf("security is enabled but the cluster has no aerospikeAccessControl")
+	}
+
+	for idx := range accessControl.Users {
+		user := &accessControl.Users[idx]
+		if user.Name != asdbv1.AdminUsername {
+			continue
+		}
+
+		secret := &corev1.Secret{}
+		if err := initp.k8sClient.Get(ctx, types.NamespacedName{Name: user.SecretName, Namespace: initp.namespace},
+			secret); err != nil {
+			return "", fmt.Errorf("failed to get secret %s of user %s: %v", user.SecretName, user.Name, err)
+		}
+
+		password, ok := secret.Data[userPasswordSecretKey]
+		if !ok {
+			return "", fmt.Errorf("key %s not found in secret %s of user %s", userPasswordSecretKey,
+				user.SecretName, user.Name)
+		}
+
+		return string(password), nil
+	}
+
+	return "", fmt.Errorf("user %s not found in the aerospikeAccessControl of the cluster", asdbv1.AdminUsername)
+}
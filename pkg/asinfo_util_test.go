@@ -0,0 +1,116 @@
+package pkg
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	asdbv1 "github.com/aerospike/aerospike-kubernetes-operator/v4/api/v1"
+)
+
+func TestInfoClientPolicy(t *testing.T) {
+	scheme, err := newScheme()
+	if err != nil {
+		t.Fatalf("newScheme() error = %v", err)
+	}
+
+	adminSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "admin-auth", Namespace: "aerospike"},
+		Data:       map[string][]byte{userPasswordSecretKey: []byte("admin-password")},
+	}
+
+	accessControl := func(users ...asdbv1.AerospikeUserSpec) *asdbv1.AerospikeAccessControlSpec {
+		return &asdbv1.AerospikeAccessControlSpec{Users: users}
+	}
+
+	const (
+		securedConf   = "security {\n}\nservice {\n    cluster-name test\n}\n"
+		unsecuredConf = "service {\n    cluster-name test\n}\n"
+	)
+
+	tests := []struct {
+		name          string
+		conf          string
+		accessControl *asdbv1.AerospikeAccessControlSpec
+		wantUser      string
+		wantPassword  string
+		wantErr       bool
+	}{
+		{
+			name: "security disabled",
+			conf: unsecuredConf,
+		},
+		{
+			name: "admin user",
+			conf: securedConf,
+			accessControl: accessControl(
+				asdbv1.AerospikeUserSpec{Name: "app", SecretName: "app-auth"},
+				asdbv1.AerospikeUserSpec{Name: asdbv1.AdminUsername, SecretName: "admin-auth"},
+			),
+			wantUser:     asdbv1.AdminUsername,
+			wantPassword: "admin-password",
+		},
+		{
+			name:    "no access control",
+			conf:    securedConf,
+			wantErr: true,
+		},
+		{
+			name:          "no admin user",
+			conf:          securedConf,
+			accessControl: accessControl(asdbv1.AerospikeUserSpec{Name: "app", SecretName: "admin-auth"}),
+			wantErr:       true,
+		},
+		{
+			name: "missing secret",
+			conf: securedConf,
+			accessControl: accessControl(
+				asdbv1.AerospikeUserSpec{Name: asdbv1.AdminUsername, SecretName: "missing"},
+			),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configVolume := t.TempDir()
+			if err := os.WriteFile(filepath.Join(configVolume, aerospikeConfFile), []byte(tt.conf), 0600); err != nil {
+				t.Fatalf("failed to write aerospike conf: %v", err)
+			}
+
+			initp := &InitParams{
+				logger:    logr.Discard(),
+				k8sClient: fake.NewClientBuilder().WithScheme(scheme).WithObjects(adminSecret).Build(),
+				namespace: "aerospike",
+				paths:     Paths{ConfigVolume: configVolume},
+				aeroCluster: &asdbv1.AerospikeCluster{
+					Spec: asdbv1.AerospikeClusterSpec{AerospikeAccessControl: tt.accessControl},
+				},
+			}
+
+			policy, err := initp.infoClientPolicy(context.Background())
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("infoClientPolicy() returned no error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("infoClientPolicy() error = %v", err)
+			}
+
+			if policy.User != tt.wantUser || policy.Password != tt.wantPassword {
+				t.Errorf("infoClientPolicy() user = %q password = %q, want %q %q", policy.User, policy.Password,
+					tt.wantUser, tt.wantPassword)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	// atomicDataDir is the symlink pointing to the current version directory, same as kubelet's configmap volumes.
+	atomicDataDir    = "..data"
+	atomicDataDirTmp = "..data_tmp"
+	// atomicVersionDirPrefix prefixes the version directories. Entries starting with ".." are never user keys.
+	atomicVersionDirPrefix = ".."
+)
+
+// writeAtomicDir replaces the content of targetDir with the given files in one atomic step, the way
+// kubelet updates configmap volumes. The files are written and synced into a new version directory,
+// the ..data symlink is swapped to it with a rename, and every file is a symlink through ..data.
+// Files not in the given set are removed, including regular files written by earlier non-atomic exports.
+func writeAtomicDir(logger logr.Logger, targetDir string, files map[string][]byte) error {
+	if err := os.MkdirAll(targetDir, 0755); err != nil { //nolint:gocritic // file permission
+		return err
+	}
+
+	for name := range files {
+		if name == "" || strings.HasPrefix(name, atomicVersionDirPrefix) || strings.ContainsRune(name, os.PathSeparator) {
+			return fmt.Errorf("invalid file name %q", name)
+		}
+	}
+
+	versionDir, err := os.MkdirTemp(targetDir, time.Now().UTC().Format(atomicVersionDirPrefix+"2006_01_02_15_04_05."))
+	if err != nil {
+		return err
+	}
+
+	if err := os.Chmod(versionDir, 0755); err != nil { //nolint:gocritic // file permission
+		return err
+	}
+
+	for name, data := range files {
+		if err := writeSyncedFile(filepath.Join(versionDir, name), data); err != nil {
+			os.RemoveAll(versionDir)
+			return err
+		}
+	}
+
+	if err := syncDir(versionDir); err != nil {
+		os.RemoveAll(versionDir)
+		return err
+	}
+
+	// Swap the data symlink to the new version.
+	dataDirTmp := filepath.Join(targetDir, atomicDataDirTmp)
+
+	if err := os.Remove(dataDirTmp); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.Symlink(filepath.Base(versionDir), dataDirTmp); err != nil {
+		return err
+	}
+
+	if err := os.Rename(dataDirTmp, filepath.Join(targetDir, atomicDataDir)); err != nil {
+		return err
+	}
+
+	// Point the visible files through the data symlink. A rename replaces regular files of the old layout atomically.
+	for name := range files {
+		link := filepath.Join(targetDir, name)
+		linkTarget := filepath.Join(atomicDataDir, name)
+
+		if current, err := os.Readlink(link); err == nil && current == linkTarget {
+			continue
+		}
+
+		linkTmp := filepath.Join(targetDir, atomicVersionDirPrefix+name+".tmp")
+
+		if err := os.Remove(linkTmp); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		if err := os.Symlink(linkTarget, linkTmp); err != nil {
+			return err
+		}
+
+		if err := os.Rename(linkTmp, link); err != nil {
+			return err
+		}
+	}
+
+	if err := syncDir(targetDir); err != nil {
+		return err
+	}
+
+	return pruneAtomicDir(logger, targetDir, filepath.Base(versionDir), files)
+}
+
+// pruneAtomicDir removes the files which are not in the current set and the old version directories.
+func pruneAtomicDir(logger logr.Logger, targetDir, currentVersion string, files map[string][]byte) error {
+	entries, err := os.ReadDir(targetDir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		switch {
+		case name == atomicDataDir || name == currentVersion:
+			continue
+
+		case strings.HasPrefix(name, atomicVersionDirPrefix):
+			if !entry.IsDir() {
+				continue
+			}
+
+		default:
+			if _, ok := files[name]; ok {
+				continue
+			}
+
+			logger.Info("Removing file no longer present in configmap", "file", name, "dir", targetDir)
+		}
+
+		if err := os.RemoveAll(filepath.Join(targetDir, name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readConfigDir reads the files of a mounted configmap directory. Hidden entries, like the ..data
+// directory of a kubelet configmap volume, are skipped and symlinks are followed.
+func readConfigDir(logger logr.Logger, dir string) (map[string][]byte, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte, len(matches))
+
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if info.IsDir() {
+			logger.Info("Skipping directory in configmap directory", "path", path)
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		files[filepath.Base(path)] = data
+	}
+
+	return files, nil
+}
+
+func writeSyncedFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) //nolint:gocritic,gosec // file permission
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func syncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	return f.Sync()
+}
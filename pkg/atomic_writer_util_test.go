@@ -0,0 +1,130 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func TestWriteAtomicDir(t *testing.T) {
+	tests := []struct {
+		name string
+		// existing are regular files written by an earlier non-atomic export.
+		existing map[string]string
+		// writes are written in order, the last one is expected in the directory.
+		writes []map[string]string
+	}{
+		{
+			name:   "empty directory",
+			writes: []map[string]string{{"aerospike.template.conf": "conf", "peers": "a"}},
+		},
+		{
+			name:     "regular files of the old layout",
+			existing: map[string]string{"aerospike.template.conf": "old", "stale": "x"},
+			writes:   []map[string]string{{"aerospike.template.conf": "conf", "peers": "a"}},
+		},
+		{
+			name: "update removes keys",
+			writes: []map[string]string{
+				{"aerospike.template.conf": "conf", "peers": "a"},
+				{"aerospike.template.conf": "conf-2"},
+			},
+		},
+		{
+			name: "update adds keys",
+			writes: []map[string]string{
+				{"aerospike.template.conf": "conf"},
+				{"aerospike.template.conf": "conf", "peers": "b"},
+				{"aerospike.template.conf": "conf-3", "peers": "c"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "configmap")
+
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			for name, data := range tt.existing {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var want map[string][]byte
+
+			for _, write := range tt.writes {
+				want = make(map[string][]byte, len(write))
+				for name, data := range write {
+					want[name] = []byte(data)
+				}
+
+				if err := writeAtomicDir(logr.Discard(), dir, want); err != nil {
+					t.Fatalf("writeAtomicDir() error = %v", err)
+				}
+			}
+
+			got, err := readConfigDir(logr.Discard(), dir)
+			if err != nil {
+				t.Fatalf("readConfigDir() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("readConfigDir() = %q, want %q", got, want)
+			}
+
+			for name := range want {
+				target, err := os.Readlink(filepath.Join(dir, name))
+				if err != nil || target != filepath.Join(atomicDataDir, name) {
+					t.Errorf("%s links to %q, %v, want %q", name, target, err, filepath.Join(atomicDataDir, name))
+				}
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			versions := 0
+
+			for _, entry := range entries {
+				if entry.IsDir() && strings.HasPrefix(entry.Name(), atomicVersionDirPrefix) {
+					versions++
+				}
+			}
+
+			if versions != 1 {
+				t.Errorf("found %d version directories, want 1", versions)
+			}
+		})
+	}
+}
+
+func TestWriteAtomicDirInvalidName(t *testing.T) {
+	for _, name := range []string{"", atomicDataDir, "..hidden", "dir/file"} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			err := writeAtomicDir(logr.Discard(), dir, map[string][]byte{name: []byte("data")})
+			if err == nil || !strings.Contains(err.Error(), "invalid file name") {
+				t.Fatalf("writeAtomicDir() error = %v, want invalid file name", err)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(entries) != 0 {
+				t.Errorf("writeAtomicDir() left %d entries in the directory", len(entries))
+			}
+		})
+	}
+}
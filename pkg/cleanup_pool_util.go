@@ -0,0 +1,119 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+)
+
+// cleanupPool runs volume cleanup jobs with a bounded number of workers.
+// The first failing job cancels the remaining ones through the pool context.
+// The failures are returned together, naming each failing volume and method,
+// and the volumes cancelled or skipped because of them are reported apart.
+type cleanupPool struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	logger    logr.Logger
+	guard     chan struct{}
+	errs      []error
+	cancelled []string
+	wg        sync.WaitGroup
+	mu        sync.Mutex
+}
+
+func newCleanupPool(ctx context.Context, logger logr.Logger, workers int) *cleanupPool {
+	if workers < 1 {
+		workers = 1
+	}
+
+	poolCtx, cancel := context.WithCancel(ctx)
+
+	return &cleanupPool{
+		ctx:    poolCtx,
+		cancel: cancel,
+		logger: logger,
+		guard:  make(chan struct{}, workers),
+	}
+}
+
+// submit runs the job once a worker is free. Jobs submitted after the pool is cancelled are not run.
+func (p *cleanupPool) submit(volumeName, method string, job func(ctx context.Context) error) {
+	select {
+	case p.guard <- struct{}{}:
+		// Both cases are ready once the pool is cancelled, and select picks one at random.
+		if p.ctx.Err() != nil {
+			<-p.guard
+			p.skip(volumeName, method, "Skipping cleanup job, pool is cancelled")
+
+			return
+		}
+	case <-p.ctx.Done():
+		p.skip(volumeName, method, "Skipping cleanup job, pool is cancelled")
+		return
+	}
+
+	p.wg.Add(1)
+
+	go func() {
+		defer func() {
+			<-p.guard
+			p.wg.Done()
+		}()
+
+		err := job(p.ctx)
+
+		switch {
+		case err == nil:
+		case errors.Is(err, context.Canceled):
+			// Interrupted by another failure or by the caller, not a failure of its own.
+			p.skip(volumeName, method, fmt.Sprintf("Cleanup job cancelled: %v", err))
+		default:
+			p.logger.Error(err, "Cleanup job failed", "volume", volumeName, "method", method)
+
+			p.mu.Lock()
+			p.errs = append(p.errs, fmt.Errorf("volume %s method %s: %w", volumeName, method, err))
+			p.mu.Unlock()
+
+			p.cancel()
+		}
+	}()
+}
+
+// skip records a job which was not run, or did not complete, because the pool was cancelled.
+func (p *cleanupPool) skip(volumeName, method, message string) {
+	p.logger.Info(message, "volume", volumeName, "method", method)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.cancelled = append(p.cancelled, fmt.Sprintf("volume %s method %s", volumeName, method))
+}
+
+// wait waits for the submitted jobs and returns the combined error of the failed ones,
+// followed by the list of the cancelled ones if any.
+func (p *cleanupPool) wait() error {
+	p.wg.Wait()
+	p.cancel()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	errs := append([]error{}, p.errs...)
+
+	if len(p.cancelled) != 0 {
+		errs = append(errs, fmt.Errorf("cancelled before completion: %s: %w", strings.Join(p.cancelled, ", "),
+			context.Canceled))
+	}
+
+	return errors.Join(errs...)
+}
+
+// stop cancels the remaining jobs and waits for the running ones to return.
+func (p *cleanupPool) stop() {
+	p.cancel()
+	p.wg.Wait()
+}
@@ -0,0 +1,134 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func TestCleanupPool(t *testing.T) {
+	errIO := errors.New("input/output error")
+
+	tests := []struct {
+		name    string
+		workers int
+		// jobs maps the volume names to the error their job returns.
+		jobs         map[string]error
+		wantErrs     []string
+		wantCanceled bool
+	}{
+		{
+			name:    "all succeed",
+			workers: 2,
+			jobs:    map[string]error{"data": nil, "logs": nil},
+		},
+		{
+			name:     "one fails",
+			workers:  2,
+			jobs:     map[string]error{"data": errIO, "logs": nil},
+			wantErrs: []string{"volume data method dd: input/output error"},
+		},
+		{
+			name:    "concurrent failures",
+			workers: 3,
+			jobs:    map[string]error{"data": errIO, "logs": errIO, "index": errIO},
+			wantErrs: []string{
+				"volume data method dd: input/output error",
+				"volume index method dd: input/output error",
+				"volume logs method dd: input/output error",
+			},
+		},
+		{
+			name:         "cancelled by failure",
+			workers:      2,
+			jobs:         map[string]error{"data": errIO, "logs": context.Canceled},
+			wantErrs:     []string{"volume data method dd: input/output error"},
+			wantCanceled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := newCleanupPool(context.Background(), logr.Discard(), tt.workers)
+			defer pool.stop()
+
+			// All the jobs run before any of them returns, the failing ones return at the same time.
+			var started sync.WaitGroup
+
+			started.Add(len(tt.jobs))
+
+			release := make(chan struct{})
+
+			for volumeName, jobErr := range tt.jobs {
+				pool.submit(volumeName, "dd", func(ctx context.Context) error {
+					started.Done()
+					<-release
+
+					if errors.Is(jobErr, context.Canceled) {
+						<-ctx.Done()
+						return fmt.Errorf("interrupted: %w", ctx.Err())
+					}
+
+					return jobErr
+				})
+			}
+
+			started.Wait()
+			close(release)
+
+			err := pool.wait()
+
+			if len(tt.wantErrs) == 0 && !tt.wantCanceled {
+				if err != nil {
+					t.Fatalf("wait() error = %v", err)
+				}
+
+				return
+			}
+
+			if err == nil {
+				t.Fatal("wait() returned no error")
+			}
+
+			for _, want := range tt.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("wait() error = %v, want it to contain %q", err, want)
+				}
+			}
+
+			if got := len(strings.Split(err.Error(), "\n")); !tt.wantCanceled && got != len(tt.wantErrs) {
+				t.Errorf("wait() returned %d errors, want %d: %v", got, len(tt.wantErrs), err)
+			}
+
+			if got := errors.Is(err, context.Canceled); got != tt.wantCanceled {
+				t.Errorf("wait() error is context.Canceled = %v, want %v: %v", got, tt.wantCanceled, err)
+			}
+
+			if len(tt.wantErrs) != 0 && !errors.Is(err, errIO) {
+				t.Errorf("wait() error = %v, want it to wrap the job error", err)
+			}
+		})
+	}
+}
+
+func TestCleanupPoolSubmitAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	pool := newCleanupPool(ctx, logr.Discard(), 1)
+
+	pool.submit("data", "dd", func(context.Context) error {
+		t.Error("job submitted to a cancelled pool was run")
+		return nil
+	})
+
+	err := pool.wait()
+	if !errors.Is(err, context.Canceled) || !strings.Contains(err.Error(), "volume data method dd") {
+		t.Errorf("wait() error = %v, want the skipped job reported as cancelled", err)
+	}
+}
@@ -19,10 +19,6 @@ import (
 	asdbv1 "github.com/aerospike/aerospike-kubernetes-operator/v4/api/v1"
 )
 
-var (
-	clusterName = os.Getenv("MY_POD_CLUSTER_NAME")
-)
-
 type InitParams struct {
 	k8sClient      client.Client
 	aeroCluster    *asdbv1.AerospikeCluster
@@ -33,23 +29,78 @@ type InitParams struct {
 	nodeID         string
 	workDir        string
 	logger         logr.Logger
+	recorder       *eventRecorder
+	report         *InitReport
+	paths          Paths
+	restart        RestartOptions
+	configMap      ConfigMapOptions
+	network        NetworkOptions
 	overrideRackID int
+	// dynamicConfigStatus is the outcome of the last dynamic config apply, reported in the pod status.
+	dynamicConfigStatus asdbv1.DynamicConfigUpdateStatus
+	// renderedConf is the last rendered aerospike.conf with the secret placeholders left unresolved.
+	renderedConf string
+	// confRolledBack is set once aerospike.conf was rolled back, the pod status then keeps the previous config hash.
+	confRolledBack bool
+	// redactKeys are the config parameters masked in logs, on top of defaultRedactKeys.
+	redactKeys []string
+	// secretValues are the values resolved from ${secret:...} placeholders, masked wherever they are logged
+	// or published whatever parameter they were substituted in.
+	secretValues []string
+	// confStorage is where the rendered aerospike.conf is published for the pod, see ConfStorageAuto.
+	confStorage string
 }
 
-func PopulateInitParams(ctx goctx.Context) (*InitParams, error) {
-	var (
-		k8sClient client.Client
-		cfg       = ctrl.GetConfigOrDie()
-		scheme    = runtime.NewScheme()
-	)
+// PopulateInitParams gathers all the required info from environment variables, k8s cluster and AerospikeCluster.
+func PopulateInitParams(ctx goctx.Context, opts *Options) (initParams *InitParams, err error) {
+	endPhase := opts.Report.startPhase("PopulateInitParams")
+	defer func() { endPhase(err) }()
+
+	logger := newLogger()
+
+	scheme, err := newScheme()
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Gathering all the required info from environment variables, k8s cluster and AerospikeCluster")
 
-	logger := ctrl.Log.WithName("init-setup")
+	k8sClient, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+
+	return newInitParams(ctx, logger, k8sClient, opts, &podEnv{
+		podName:     os.Getenv("MY_POD_NAME"),
+		namespace:   os.Getenv("MY_POD_NAMESPACE"),
+		clusterName: os.Getenv("MY_POD_CLUSTER_NAME"),
+		hostIP:      os.Getenv("MY_HOST_IP"),
+		podIP:       os.Getenv("MY_POD_IP"),
+	})
+}
+
+// podEnv holds the pod identity which is otherwise passed to the init container through environment variables.
+type podEnv struct {
+	podName     string
+	namespace   string
+	clusterName string
+	hostIP      string
+	podIP       string
+}
+
+func newLogger() logr.Logger {
 	opts := zap.Options{
 		Development: true,
 	}
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	return ctrl.Log.WithName("init-setup")
+}
+
+func newScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+
 	if err := clientgoscheme.AddToScheme(scheme); err != nil {
 		return nil, err
 	}
@@ -58,18 +109,18 @@ func PopulateInitParams(ctx goctx.Context) (*InitParams, error) {
 		return nil, err
 	}
 
-	logger.Info("Gathering all the required info from environment variables, k8s cluster and AerospikeCluster")
+	return scheme, nil
+}
 
-	var err error
-	if k8sClient, err = client.New(
-		cfg, client.Options{Scheme: scheme},
-	); err != nil {
+func newInitParams(ctx goctx.Context, logger logr.Logger, k8sClient client.Client, opts *Options,
+	env *podEnv) (*InitParams, error) {
+	if err := validateConfStorage(opts.ConfStorage); err != nil {
 		return nil, err
 	}
 
-	podName := os.Getenv("MY_POD_NAME")
-	namespace := os.Getenv("MY_POD_NAMESPACE")
-	clusterNamespacedName := getNamespacedName(clusterName, namespace)
+	podName := env.podName
+	namespace := env.namespace
+	clusterNamespacedName := getNamespacedName(env.clusterName, namespace)
 
 	aeroCluster, err := getCluster(ctx, k8sClient, clusterNamespacedName)
 	if err != nil {
@@ -137,14 +188,26 @@ func PopulateInitParams(ctx goctx.Context) (*InitParams, error) {
 		nodeID:         nodeID,
 		workDir:        workDir,
 		logger:         logger,
+		recorder:       newEventRecorder(k8sClient, logger, podName, namespace, aeroCluster),
+		report:         opts.Report,
+		paths:          opts.Paths,
+		restart:        opts.Restart,
+		configMap:      opts.ConfigMap,
+		network:        opts.Network,
+		redactKeys:     opts.RedactKeys,
+		confStorage:    opts.ConfStorage,
 		overrideRackID: overrideRackID,
 	}
 
-	if err := initParams.setNetworkInfo(ctx); err != nil {
+	if err := initParams.setNetworkInfo(ctx, env.hostIP, env.podIP); err != nil {
 		return nil, err
 	}
 
 	logger.Info("Gathered all the required info")
 
+	initParams.recorder.normal(ctx, reasonNodeIdentity, "Selected rack %d and node ID %s",
+		rack.ID, nodeID)
+	initParams.report.setNode(nodeID, rack.ID)
+
 	return &initParams, nil
 }
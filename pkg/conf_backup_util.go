@@ -0,0 +1,213 @@
+package pkg
+
+import (
+	goctx "context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	// confBackupsToKeep is the number of aerospike.conf versions kept in the backup directory.
+	confBackupsToKeep = 5
+	// confBackupTimeFormat names the backup versions so that they sort chronologically.
+	confBackupTimeFormat = "20060102T150405.000000000Z"
+
+	// confBackupHashFile holds the SHA-256 of the aerospike.conf a backup was resolved to, to recognise it
+	// without storing the resolved secret values.
+	confBackupHashFile = "aerospike.conf.sha256"
+
+	confRollbackAnnotation = "aerospike.com/conf-rollback"
+)
+
+// errASDStartFailed is returned when the Aerospike server does not come back healthy after a restart.
+var errASDStartFailed = errors.New("aerospike server did not start")
+
+// confRollback is the report of a config rollback as published in the pod annotation.
+type confRollback struct {
+	Time           string `json:"time"`
+	Reason         string `json:"reason"`
+	RestoredBackup string `json:"restoredBackup"`
+}
+
+// saveConfBackup stores the given aerospike.conf, with its secret placeholders unresolved, and the template
+// it was rendered from as a new backup version, and prunes the oldest versions. The template is optional.
+// Only the hash of the resolved aerospike.conf is stored. Returns the backup version directory.
+func (initp *InitParams) saveConfBackup(conf, resolvedConf, template []byte) (string, error) {
+	backupDir := filepath.Join(initp.paths.confBackupDir(), time.Now().UTC().Format(confBackupTimeFormat))
+
+	if err := os.MkdirAll(backupDir, 0700); err != nil {
+		return "", err
+	}
+
+	if err := os.WriteFile(filepath.Join(backupDir, aerospikeConfFile), conf, 0600); err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(resolvedConf)
+	if err := os.WriteFile(filepath.Join(backupDir, confBackupHashFile), []byte(hex.EncodeToString(hash[:])),
+		0600); err != nil {
+		return "", err
+	}
+
+	if template != nil {
+		if err := os.WriteFile(filepath.Join(backupDir, aerospikeTemplateConfFile), template, 0600); err != nil {
+			return "", err
+		}
+	}
+
+	versions, err := initp.confBackups()
+	if err != nil {
+		return "", err
+	}
+
+	for len(versions) > confBackupsToKeep {
+		if err := os.RemoveAll(versions[0]); err != nil {
+			return "", err
+		}
+
+		initp.logger.Info("Pruned aerospike conf backup", "backup", versions[0])
+
+		versions = versions[1:]
+	}
+
+	return backupDir, nil
+}
+
+// confBackups returns the backup version directories, oldest first.
+func (initp *InitParams) confBackups() ([]string, error) {
+	entries, err := os.ReadDir(initp.paths.confBackupDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	versions := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			versions = append(versions, filepath.Join(initp.paths.confBackupDir(), entry.Name()))
+		}
+	}
+
+	sort.Strings(versions)
+
+	return versions, nil
+}
+
+// lastGoodConfBackup returns the backup of the aerospike.conf the server is currently running with.
+// The current aerospike.conf is backed up first if it is not the latest version,
+// e.g. when it was rendered before backups were introduced.
+func (initp *InitParams) lastGoodConfBackup() (string, error) {
+	backupDir, err := initp.currentConfBackup()
+	if err != nil || backupDir != "" {
+		return backupDir, err
+	}
+
+	conf, err := os.ReadFile(initp.paths.aerospikeConf())
+	if err != nil {
+		return "", err
+	}
+
+	// Not rendered by this init, the placeholders are resolved already.
+	return initp.saveConfBackup(conf, conf, nil)
+}
+
+// currentConfBackup returns the latest backup version if it holds the current aerospike.conf, else "".
+func (initp *InitParams) currentConfBackup() (string, error) {
+	conf, err := os.ReadFile(initp.paths.aerospikeConf())
+	if err != nil {
+		return "", err
+	}
+
+	versions, err := initp.confBackups()
+	if err != nil || len(versions) == 0 {
+		return "", err
+	}
+
+	latest := versions[len(versions)-1]
+	hash := sha256.Sum256(conf)
+
+	backupHash, err := os.ReadFile(filepath.Join(latest, confBackupHashFile))
+	if err == nil && string(backupHash) == hex.EncodeToString(hash[:]) {
+		return latest, nil
+	}
+
+	return "", nil
+}
+
+// restoreConfBackup replaces aerospike.conf with the one from the given backup version,
+// resolving its secret placeholders again.
+func (initp *InitParams) restoreConfBackup(ctx goctx.Context, backupDir string) error {
+	conf, err := os.ReadFile(filepath.Join(backupDir, aerospikeConfFile))
+	if err != nil {
+		return err
+	}
+
+	resolvedConf, err := initp.resolveSecretPlaceholders(ctx, string(conf))
+	if err != nil {
+		return err
+	}
+
+	tmpFile := initp.paths.aerospikeConf() + ".tmp"
+
+	if err := os.WriteFile(tmpFile, []byte(resolvedConf), 0644); err != nil { //nolint:gocritic,gosec // file permission
+		return err
+	}
+
+	if err := os.Rename(tmpFile, initp.paths.aerospikeConf()); err != nil {
+		return err
+	}
+
+	// The backup holds the placeholders, publish these.
+	initp.renderedConf = string(conf)
+
+	return nil
+}
+
+// rollbackConf restores the last known good aerospike.conf after a failed warm restart,
+// restarts the Aerospike server again and reports the rollback on the pod and in the pod status.
+// The restart error is always returned, the operator must not consider the new config as applied.
+func (initp *InitParams) rollbackConf(ctx goctx.Context, backupDir string, restartErr error) error {
+	initp.logger.Error(restartErr, "Warm restart failed, rolling back aerospike conf", "backup", backupDir)
+
+	if err := initp.restoreConfBackup(ctx, backupDir); err != nil {
+		return fmt.Errorf("%v, failed to restore aerospike conf backup %s: %v", restartErr, backupDir, err)
+	}
+
+	// The pod status must describe the restored aerospike.conf, not the rendered one.
+	initp.confRolledBack = true
+
+	rollback := confRollback{
+		Time:           time.Now().UTC().Format(time.RFC3339),
+		Reason:         restartErr.Error(),
+		RestoredBackup: filepath.Base(backupDir),
+	}
+
+	if err := initp.patchPodAnnotation(ctx, confRollbackAnnotation, rollback); err != nil {
+		initp.logger.Error(err, "Failed to report aerospike conf rollback", "annotation", confRollbackAnnotation)
+	}
+
+	initp.recorder.warning(ctx, reasonConfRolledBack, "Restored aerospike.conf backup %s after failed warm restart: %v",
+		rollback.RestoredBackup, restartErr)
+
+	if err := initp.signalASDRestart(ctx); err != nil {
+		return fmt.Errorf("%v, rolled back aerospike conf to backup %s but restart failed: %v",
+			restartErr, rollback.RestoredBackup, err)
+	}
+
+	if err := initp.manageVolumesAndUpdateStatus(ctx, "confRollback"); err != nil {
+		return fmt.Errorf("%v, rolled back aerospike conf to backup %s but failed to update pod status: %v",
+			restartErr, rollback.RestoredBackup, err)
+	}
+
+	return fmt.Errorf("%v, rolled back aerospike conf to backup %s", restartErr, rollback.RestoredBackup)
+}
@@ -0,0 +1,74 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aerospike/aerospike-kubernetes-operator/v4/pkg/utils"
+)
+
+// redactedValue replaces the value of sensitive parameters.
+const redactedValue = "<redacted>"
+
+// defaultRedactKeys lists the Aerospike server parameters which accept a credential inline. Only the tls
+// key-file-password takes the password itself, next to the file: and env: references. The other credential
+// parameters, e.g. auth-password-file of xdr, query-user-password-file of ldap and vault-token-file, take a path.
+// More parameters are masked with --redact-keys.
+var defaultRedactKeys = []string{
+	"key-file-password",
+}
+
+// redactConf masks the value of the given parameters in aerospike.conf, in any section.
+// Secret placeholders are kept since they only reference the secret.
+func redactConf(conf string, keys []string) (string, error) {
+	root, err := parseAerospikeConf(conf)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse aerospike conf for redaction: %v", err)
+	}
+
+	var walk func(entry *confEntry)
+
+	walk = func(entry *confEntry) {
+		for _, child := range entry.children {
+			switch child.kind {
+			case confParam:
+				if utils.ContainsString(keys, child.name) && !secretPlaceholderRegex.MatchString(child.value) {
+					child.value = redactedValue
+				}
+
+			case confSection:
+				walk(child)
+			}
+		}
+	}
+
+	walk(root)
+
+	return root.String(), nil
+}
+
+// redact returns aerospike.conf with the default and configured sensitive parameters and the resolved
+// secret values masked, to be logged.
+func (initp *InitParams) redact(conf string) (string, error) {
+	redacted, err := redactConf(conf, initp.redactedKeys())
+	if err != nil {
+		return "", err
+	}
+
+	return initp.redactSecretValues(redacted), nil
+}
+
+// redactedKeys returns the default and configured sensitive parameters.
+func (initp *InitParams) redactedKeys() []string {
+	return append(append([]string{}, defaultRedactKeys...), initp.redactKeys...)
+}
+
+// redactSecretValues masks the values resolved from ${secret:...} placeholders in the given text,
+// whatever parameter they were substituted in.
+func (initp *InitParams) redactSecretValues(text string) string {
+	for _, value := range initp.secretValues {
+		text = strings.ReplaceAll(text, value, redactedValue)
+	}
+
+	return text
+}
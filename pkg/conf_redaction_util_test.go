@@ -0,0 +1,128 @@
+package pkg
+
+import "testing"
+
+func TestRedactConf(t *testing.T) {
+	tests := []struct {
+		name string
+		conf string
+		keys []string
+		want string
+	}{
+		{
+			name: "default keys",
+			conf: "network {\n" +
+				"    tls tls1 {\n" +
+				"        key-file    /etc/aerospike/secrets/key.pem\n" +
+				"        key-file-password    secret\n" +
+				"    }\n" +
+				"}\n",
+			keys: defaultRedactKeys,
+			want: "network {\n" +
+				"    tls tls1 {\n" +
+				"        key-file    /etc/aerospike/secrets/key.pem\n" +
+				"        key-file-password    " + redactedValue + "\n" +
+				"    }\n" +
+				"}\n",
+		},
+		{
+			name: "secret placeholders are kept",
+			conf: "network {\n" +
+				"    tls tls1 {\n" +
+				"        key-file-password    ${secret:tls-creds/password}\n" +
+				"    }\n" +
+				"}\n",
+			keys: defaultRedactKeys,
+			want: "network {\n" +
+				"    tls tls1 {\n" +
+				"        key-file-password    ${secret:tls-creds/password}\n" +
+				"    }\n" +
+				"}\n",
+		},
+		{
+			name: "extra keys",
+			conf: "xdr {\n" +
+				"    dc dc1 {\n" +
+				"        auth-user    admin\n" +
+				"        auth-password-file    /etc/aerospike/secrets/password\n" +
+				"    }\n" +
+				"}\n",
+			keys: append([]string{"auth-user"}, defaultRedactKeys...),
+			want: "xdr {\n" +
+				"    dc dc1 {\n" +
+				"        auth-user    " + redactedValue + "\n" +
+				"        auth-password-file    /etc/aerospike/secrets/password\n" +
+				"    }\n" +
+				"}\n",
+		},
+		{
+			name: "no sensitive keys",
+			conf: "service {\n" +
+				"    cluster-name    test\n" +
+				"}\n",
+			keys: defaultRedactKeys,
+			want: "service {\n" +
+				"    cluster-name    test\n" +
+				"}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := redactConf(tt.conf, tt.keys)
+			if err != nil {
+				t.Fatalf("redactConf() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("redactConf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactConfInvalid(t *testing.T) {
+	if _, err := redactConf("service {\n", defaultRedactKeys); err == nil {
+		t.Error("redactConf() of unclosed section returned no error")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	initp := &InitParams{
+		redactKeys:   []string{"auth-user"},
+		secretValues: []string{"s3cr3t"},
+	}
+
+	conf := "xdr {\n" +
+		"    dc dc1 {\n" +
+		"        auth-user    admin\n" +
+		"        node-address-port    s3cr3t.example.com 3000\n" +
+		"    }\n" +
+		"}\n" +
+		"network {\n" +
+		"    tls tls1 {\n" +
+		"        key-file-password    password\n" +
+		"    }\n" +
+		"}\n"
+
+	want := "xdr {\n" +
+		"    dc dc1 {\n" +
+		"        auth-user    " + redactedValue + "\n" +
+		"        node-address-port    " + redactedValue + ".example.com 3000\n" +
+		"    }\n" +
+		"}\n" +
+		"network {\n" +
+		"    tls tls1 {\n" +
+		"        key-file-password    " + redactedValue + "\n" +
+		"    }\n" +
+		"}\n"
+
+	got, err := initp.redact(conf)
+	if err != nil {
+		t.Fatalf("redact() error = %v", err)
+	}
+
+	if got != want {
+		t.Errorf("redact() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,207 @@
+package pkg
+
+import (
+	"bytes"
+	"compress/gzip"
+	goctx "context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// Storage modes of the rendered aerospike.conf published for the pod.
+const (
+	// ConfStorageAuto publishes the config in the annotation and falls back to gzip, then to a configmap,
+	// when it does not fit in the 256KiB total of the pod annotations.
+	ConfStorageAuto = "auto"
+	// ConfStorageAnnotation publishes the plain config in the aerospikeConf annotation read by the operator.
+	ConfStorageAnnotation = "annotation"
+	// ConfStorageGzip publishes the gzip compressed, base64 encoded config in an annotation.
+	ConfStorageGzip = "gzip"
+	// ConfStorageConfigMap publishes the config in a configmap owned by the pod.
+	ConfStorageConfigMap = "configmap"
+	// ConfStorageHash publishes only the sha256 hash of the config in an annotation.
+	ConfStorageHash = "hash"
+)
+
+const (
+	confAnnotation          = "aerospikeConf"
+	confGzipAnnotation      = "aerospike.com/aerospike-conf-gzip"
+	confConfigMapAnnotation = "aerospike.com/aerospike-conf-configmap"
+	confHashAnnotation      = "aerospike.com/aerospike-conf-hash"
+)
+
+// validateConfStorage returns an error if the storage mode is unknown.
+func validateConfStorage(mode string) error {
+	switch mode {
+	case ConfStorageAuto, ConfStorageAnnotation, ConfStorageGzip, ConfStorageConfigMap, ConfStorageHash:
+		return nil
+	default:
+		return fmt.Errorf("invalid conf storage mode %q", mode)
+	}
+}
+
+// confAnnotations stores the config as per the configured storage mode and returns the pod annotations to set.
+// Annotations of the other modes are returned with an empty value, to be removed from the pod.
+func (initp *InitParams) confAnnotations(ctx goctx.Context, pod *corev1.Pod, conf string) (map[string]string, error) {
+	annotations := map[string]string{
+		confAnnotation:          "",
+		confGzipAnnotation:      "",
+		confConfigMapAnnotation: "",
+		confHashAnnotation:      "",
+	}
+
+	mode := initp.confStorage
+
+	var compressed string
+
+	if mode == ConfStorageAuto {
+		mode = ConfStorageAnnotation
+
+		if !fitsInPodAnnotations(pod, confAnnotation, conf) {
+			var err error
+
+			if compressed, err = gzipBase64(conf); err != nil {
+				return nil, err
+			}
+
+			mode = ConfStorageGzip
+			if !fitsInPodAnnotations(pod, confGzipAnnotation, compressed) {
+				mode = ConfStorageConfigMap
+			}
+
+			initp.logger.Info("Aerospike conf is too large for an annotation", "size", len(conf),
+				"compressed-size", len(compressed), "storage", mode)
+		}
+	}
+
+	switch mode {
+	case ConfStorageAnnotation:
+		annotations[confAnnotation] = conf
+
+	case ConfStorageGzip:
+		if compressed == "" {
+			var err error
+
+			if compressed, err = gzipBase64(conf); err != nil {
+				return nil, err
+			}
+		}
+
+		annotations[confGzipAnnotation] = compressed
+
+	case ConfStorageConfigMap:
+		cmName, err := initp.storeConfConfigMap(ctx, pod, conf)
+		if err != nil {
+			return nil, err
+		}
+
+		annotations[confConfigMapAnnotation] = cmName
+
+	case ConfStorageHash:
+		hash := sha256.Sum256([]byte(conf))
+		annotations[confHashAnnotation] = hex.EncodeToString(hash[:])
+
+	default:
+		return nil, validateConfStorage(mode)
+	}
+
+	if cmName, ok := pod.Annotations[confConfigMapAnnotation]; ok && mode != ConfStorageConfigMap {
+		initp.deleteConfConfigMap(ctx, cmName)
+	}
+
+	return annotations, nil
+}
+
+// fitsInPodAnnotations returns true if the annotation fits in the total size limit of the pod annotations,
+// next to the annotations the pod already has. The conf annotations it replaces are not counted.
+func fitsInPodAnnotations(pod *corev1.Pod, key, value string) bool {
+	size := len(key) + len(value)
+
+	for name, annotation := range pod.Annotations {
+		switch name {
+		case confAnnotation, confGzipAnnotation, confConfigMapAnnotation, confHashAnnotation:
+			continue
+		}
+
+		size += len(name) + len(annotation)
+	}
+
+	return size <= apivalidation.TotalAnnotationSizeLimitB
+}
+
+// storeConfConfigMap creates or updates the configmap holding the config of the pod.
+// The configmap is owned by the pod and garbage collected with it.
+func (initp *InitParams) storeConfConfigMap(ctx goctx.Context, pod *corev1.Pod, conf string) (string, error) {
+	configMap := &corev1.ConfigMap{}
+	name := pod.Name + "-aerospike-conf"
+
+	err := initp.k8sClient.Get(ctx, getNamespacedName(name, pod.Namespace), configMap)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", err
+	}
+
+	exists := err == nil
+
+	configMap.Name = name
+	configMap.Namespace = pod.Namespace
+	configMap.OwnerReferences = []metav1.OwnerReference{
+		{
+			APIVersion: "v1",
+			Kind:       "Pod",
+			Name:       pod.Name,
+			UID:        pod.UID,
+			Controller: ptr.To(true),
+		},
+	}
+	configMap.Data = map[string]string{aerospikeConfFile: conf}
+
+	if exists {
+		err = initp.k8sClient.Update(ctx, configMap)
+	} else {
+		err = initp.k8sClient.Create(ctx, configMap)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to store aerospike conf in configmap %s: %v", name, err)
+	}
+
+	initp.logger.Info("Stored aerospike conf in configmap", "cm-name", name, "size", len(conf))
+
+	return name, nil
+}
+
+// deleteConfConfigMap deletes the configmap of an earlier configmap storage. Failures are only logged,
+// the configmap is garbage collected with the pod anyway.
+func (initp *InitParams) deleteConfConfigMap(ctx goctx.Context, name string) {
+	configMap := &corev1.ConfigMap{}
+	configMap.Name = name
+	configMap.Namespace = initp.namespace
+
+	if err := initp.k8sClient.Delete(ctx, configMap); err != nil && !apierrors.IsNotFound(err) {
+		initp.logger.Error(err, "Failed to delete stale aerospike conf configmap", "cm-name", name)
+	}
+}
+
+func gzipBase64(data string) (string, error) {
+	var buf bytes.Buffer
+
+	writer := gzip.NewWriter(&buf)
+
+	if _, err := writer.Write([]byte(data)); err != nil {
+		return "", err
+	}
+
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
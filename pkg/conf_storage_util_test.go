@@ -0,0 +1,252 @@
+package pkg
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConfAnnotations(t *testing.T) {
+	scheme, err := newScheme()
+	if err != nil {
+		t.Fatalf("newScheme() error = %v", err)
+	}
+
+	random := make([]byte, apivalidation.TotalAnnotationSizeLimitB)
+	if _, err := rand.Read(random); err != nil {
+		t.Fatal(err)
+	}
+
+	smallConf := "service {\n\tcluster-name test\n}\n"
+	// Compresses well below the limit.
+	compressibleConf := strings.Repeat(smallConf, apivalidation.TotalAnnotationSizeLimitB/len(smallConf)+1)
+	// Does not fit, even compressed.
+	incompressibleConf := base64.StdEncoding.EncodeToString(random)
+
+	tests := []struct {
+		name string
+		mode string
+		conf string
+		// podAnnotations are the annotations the pod already has.
+		podAnnotations map[string]string
+		wantStorage    string
+		// wantDeleted is true if an existing conf configmap must be deleted.
+		wantDeleted bool
+	}{
+		{
+			name:        "annotation",
+			mode:        ConfStorageAnnotation,
+			conf:        smallConf,
+			wantStorage: confAnnotation,
+		},
+		{
+			name:        "gzip",
+			mode:        ConfStorageGzip,
+			conf:        smallConf,
+			wantStorage: confGzipAnnotation,
+		},
+		{
+			name:        "configmap",
+			mode:        ConfStorageConfigMap,
+			conf:        smallConf,
+			wantStorage: confConfigMapAnnotation,
+		},
+		{
+			name:        "hash",
+			mode:        ConfStorageHash,
+			conf:        smallConf,
+			wantStorage: confHashAnnotation,
+		},
+		{
+			name:        "auto small",
+			mode:        ConfStorageAuto,
+			conf:        smallConf,
+			wantStorage: confAnnotation,
+		},
+		{
+			name:        "auto compressible",
+			mode:        ConfStorageAuto,
+			conf:        compressibleConf,
+			wantStorage: confGzipAnnotation,
+		},
+		{
+			name:        "auto incompressible",
+			mode:        ConfStorageAuto,
+			conf:        incompressibleConf,
+			wantStorage: confConfigMapAnnotation,
+		},
+		{
+			name:           "auto small next to large annotations",
+			mode:           ConfStorageAuto,
+			conf:           smallConf,
+			podAnnotations: map[string]string{"large": strings.Repeat("x", apivalidation.TotalAnnotationSizeLimitB-20)},
+			wantStorage:    confConfigMapAnnotation,
+		},
+		{
+			name:           "auto replaces large conf annotation",
+			mode:           ConfStorageAuto,
+			conf:           smallConf,
+			podAnnotations: map[string]string{confAnnotation: strings.Repeat("x", apivalidation.TotalAnnotationSizeLimitB)},
+			wantStorage:    confAnnotation,
+		},
+		{
+			name:           "configmap no longer used",
+			mode:           ConfStorageAnnotation,
+			conf:           smallConf,
+			podAnnotations: map[string]string{confConfigMapAnnotation: "aerospike-1-0-aerospike-conf"},
+			wantStorage:    confAnnotation,
+			wantDeleted:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "aerospike-1-0",
+					Namespace:   "aerospike",
+					UID:         "pod-uid",
+					Annotations: tt.podAnnotations,
+				},
+			}
+
+			staleConfigMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "aerospike-1-0-aerospike-conf", Namespace: "aerospike"},
+				Data:       map[string]string{aerospikeConfFile: "old"},
+			}
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod, staleConfigMap).Build()
+
+			initp := &InitParams{
+				logger:      logr.Discard(),
+				k8sClient:   k8sClient,
+				namespace:   "aerospike",
+				confStorage: tt.mode,
+			}
+
+			annotations, err := initp.confAnnotations(context.Background(), pod, tt.conf)
+			if err != nil {
+				t.Fatalf("confAnnotations() error = %v", err)
+			}
+
+			for key, value := range annotations {
+				if key != tt.wantStorage && value != "" {
+					t.Errorf("confAnnotations() set annotation %s, want only %s", key, tt.wantStorage)
+				}
+			}
+
+			value := annotations[tt.wantStorage]
+
+			switch tt.wantStorage {
+			case confAnnotation:
+				if value != tt.conf {
+					t.Errorf("annotation %s does not hold the conf", confAnnotation)
+				}
+
+			case confGzipAnnotation:
+				if got := gunzipBase64(t, value); got != tt.conf {
+					t.Errorf("annotation %s does not hold the compressed conf", confGzipAnnotation)
+				}
+
+			case confConfigMapAnnotation:
+				configMap := &corev1.ConfigMap{}
+				if err := k8sClient.Get(context.Background(), getNamespacedName(value, "aerospike"),
+					configMap); err != nil {
+					t.Fatalf("failed to get conf configmap %s: %v", value, err)
+				}
+
+				if configMap.Data[aerospikeConfFile] != tt.conf {
+					t.Errorf("configmap %s does not hold the conf", value)
+				}
+
+				if len(configMap.OwnerReferences) != 1 || configMap.OwnerReferences[0].UID != pod.UID {
+					t.Errorf("configmap %s owner references = %v, want the pod", value, configMap.OwnerReferences)
+				}
+
+			case confHashAnnotation:
+				if len(value) != 64 {
+					t.Errorf("annotation %s = %q, want a sha256 hash", confHashAnnotation, value)
+				}
+			}
+
+			err = k8sClient.Get(context.Background(), getNamespacedName(staleConfigMap.Name, "aerospike"),
+				&corev1.ConfigMap{})
+			if deleted := apierrors.IsNotFound(err); deleted != tt.wantDeleted {
+				t.Errorf("conf configmap deleted = %v, want %v, error = %v", deleted, tt.wantDeleted, err)
+			}
+		})
+	}
+}
+
+func TestFitsInPodAnnotations(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		value       string
+		want        bool
+	}{
+		{
+			name:  "at the limit",
+			value: strings.Repeat("x", apivalidation.TotalAnnotationSizeLimitB-len(confAnnotation)),
+			want:  true,
+		},
+		{
+			name:  "over the limit",
+			value: strings.Repeat("x", apivalidation.TotalAnnotationSizeLimitB-len(confAnnotation)+1),
+		},
+		{
+			name:        "other annotations counted",
+			annotations: map[string]string{"key": "value"},
+			value:       strings.Repeat("x", apivalidation.TotalAnnotationSizeLimitB-len(confAnnotation)-7),
+		},
+		{
+			name:        "conf annotations not counted",
+			annotations: map[string]string{confAnnotation: "old", confGzipAnnotation: "old", confHashAnnotation: "old"},
+			value:       strings.Repeat("x", apivalidation.TotalAnnotationSizeLimitB-len(confAnnotation)),
+			want:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+
+			if got := fitsInPodAnnotations(pod, confAnnotation, tt.value); got != tt.want {
+				t.Errorf("fitsInPodAnnotations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func gunzipBase64(t *testing.T, data string) string {
+	t.Helper()
+
+	compressed, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		t.Fatalf("failed to decode base64: %v", err)
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatalf("failed to read gzip: %v", err)
+	}
+
+	plain, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to read gzip: %v", err)
+	}
+
+	return string(plain)
+}
@@ -19,7 +19,7 @@ func (initp *InitParams) copyTemplates(source, destination string) error {
 
 	initp.logger.Info("Installing aerospike.conf", "source", source, "destination", destination)
 
-	filesToCopy := [2]string{"aerospike.template.conf", "peers"}
+	filesToCopy := [2]string{aerospikeTemplateConfFile, peersFile}
 	for _, file := range filesToCopy {
 		path := filepath.Join(source, file)
 
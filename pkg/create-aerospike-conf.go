@@ -2,10 +2,11 @@ package pkg
 
 import (
 	"bufio"
+	"bytes"
+	goctx "context"
 	_ "embed"
 	"fmt"
 	"os"
-	"regexp"
 	"strconv"
 	"strings"
 
@@ -19,70 +20,106 @@ var opensslCnf []byte
 var fipsCnf []byte
 
 const (
-	aerospikeTemplateConf = "/etc/aerospike/aerospike.template.conf"
-	aerospikeConf         = "/etc/aerospike/aerospike.conf"
-	peers                 = "/etc/aerospike/peers"
-	aerospikeOpensslCnf   = "/etc/aerospike/openssl.cnf"
-	aerospikeFipsCnf      = "/etc/aerospike/fips.cnf"
-	access                = "access"
-	alternateAccess       = "alternate-access"
-	tlsAccess             = "tls-access"
-	tlsAlternateAccess    = "tls-alternate-access"
+	aerospikeTemplateConfFile = "aerospike.template.conf"
+	aerospikeConfFile         = "aerospike.conf"
+	peersFile                 = "peers"
+	access                    = "access"
+	alternateAccess           = "alternate-access"
+	tlsAccess                 = "tls-access"
+	tlsAlternateAccess        = "tls-alternate-access"
 )
 
-func (initp *InitParams) createAerospikeConf() error {
-	data, err := os.ReadFile(aerospikeTemplateConf)
+func (initp *InitParams) createAerospikeConf(ctx goctx.Context) error {
+	data, err := os.ReadFile(initp.paths.aerospikeTemplateConf())
 	if err != nil {
 		return err
 	}
 
-	confString := string(data)
+	peersData, err := os.ReadFile(initp.paths.peers())
+	if err != nil {
+		return err
+	}
+
+	confString, err := initp.renderAerospikeConf(data, peersData)
+	if err != nil {
+		return err
+	}
+
+	resolvedConf, err := initp.resolveSecretPlaceholders(ctx, confString)
+	if err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(initp.paths.aerospikeConf(), []byte(resolvedConf), 0644); err != nil { //nolint:gocritic,gosec // file permission
+		return err
+	}
+
+	// Secret values are only written to aerospike.conf, logs and annotations get the placeholders.
+	initp.renderedConf = confString
+
+	backupDir, err := initp.saveConfBackup([]byte(confString), []byte(resolvedConf), data)
+	if err != nil {
+		return fmt.Errorf("failed to backup aerospike conf: %v", err)
+	}
+
+	initp.logger.Info("Saved aerospike conf backup", "backup", backupDir)
+
+	if err := os.Remove(initp.paths.aerospikeTemplateConf()); err != nil {
+		return err
+	}
+
+	redactedConf, err := initp.redact(confString)
+	if err != nil {
+		return err
+	}
+
+	initp.logger.Info(fmt.Sprintf("Final aerospike conf file %s: \n%s", initp.paths.aerospikeConf(), redactedConf))
+
+	return nil
+}
 
+// renderAerospikeConf substitutes the pod specific values in the given template and returns the final
+// aerospike.conf content. It has no side effects other than recording the computed access endpoints.
+func (initp *InitParams) renderAerospikeConf(template, peersData []byte) (string, error) {
 	// Update node ids in configuration file
-	confString = strings.ReplaceAll(confString, "ENV_NODE_ID", initp.nodeID)
+	conf, err := parseAerospikeConf(strings.ReplaceAll(string(template), "ENV_NODE_ID", initp.nodeID))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse aerospike conf template: %v", err)
+	}
 
 	if initp.networkInfo.servicePort != 0 {
-		confString = initp.substituteEndpoint(
+		initp.substituteEndpoint(
 			initp.networkInfo.networkPolicy.AccessType, access, initp.networkInfo.configureAccessIP,
-			initp.networkInfo.customAccessNetworkIPs, confString)
-		confString = initp.substituteEndpoint(
+			initp.networkInfo.customAccessNetworkIPs, conf)
+		initp.substituteEndpoint(
 			initp.networkInfo.networkPolicy.AlternateAccessType, alternateAccess, initp.networkInfo.configuredAlterAccessIP,
-			initp.networkInfo.customAlternateAccessNetworkIPs, confString)
+			initp.networkInfo.customAlternateAccessNetworkIPs, conf)
 	}
 
 	if initp.networkInfo.serviceTLSPort != 0 {
-		confString = initp.substituteEndpoint(
+		initp.substituteEndpoint(
 			initp.networkInfo.networkPolicy.TLSAccessType, tlsAccess, initp.networkInfo.configureAccessIP,
-			initp.networkInfo.customTLSAccessNetworkIPs, confString)
-		confString = initp.substituteEndpoint(
+			initp.networkInfo.customTLSAccessNetworkIPs, conf)
+		initp.substituteEndpoint(
 			initp.networkInfo.networkPolicy.TLSAlternateAccessType, tlsAlternateAccess,
-			initp.networkInfo.configuredAlterAccessIP, initp.networkInfo.customTLSAlternateAccessNetworkIPs, confString)
+			initp.networkInfo.configuredAlterAccessIP, initp.networkInfo.customTLSAlternateAccessNetworkIPs, conf)
 	}
 
 	if initp.networkInfo.fabricPort != 0 &&
 		initp.networkInfo.networkPolicy.FabricType == asdbv1.AerospikeNetworkTypeCustomInterface {
 		for _, ip := range initp.networkInfo.customFabricNetworkIPs {
-			confString = strings.ReplaceAll(confString, "fabric {",
-				fmt.Sprintf("fabric {\n        address %s", ip))
+			prependNetworkParam(conf, "fabric", "address", ip)
 		}
 	}
 
 	if initp.networkInfo.fabricTLSPort != 0 &&
 		initp.networkInfo.networkPolicy.TLSFabricType == asdbv1.AerospikeNetworkTypeCustomInterface {
 		for _, ip := range initp.networkInfo.customTLSFabricNetworkIPs {
-			confString = strings.ReplaceAll(confString, "fabric {",
-				fmt.Sprintf("fabric {\n        tls-address %s", ip))
+			prependNetworkParam(conf, "fabric", "tls-address", ip)
 		}
 	}
 
-	readFile, err := os.Open(peers)
-	if err != nil {
-		return err
-	}
-
-	defer readFile.Close()
-
-	fileScanner := bufio.NewScanner(readFile)
+	fileScanner := bufio.NewScanner(bytes.NewReader(peersData))
 
 	// Update mesh seeds in the configuration file
 	for fileScanner.Scan() {
@@ -92,71 +129,68 @@ func (initp *InitParams) createAerospikeConf() error {
 		}
 
 		if initp.networkInfo.heartBeatPort != 0 {
-			confString = strings.ReplaceAll(confString, "heartbeat {",
-				fmt.Sprintf("heartbeat {\n        mesh-seed-address-port %s %d", peer, initp.networkInfo.heartBeatPort))
+			prependNetworkParam(conf, "heartbeat", "mesh-seed-address-port",
+				fmt.Sprintf("%s %d", peer, initp.networkInfo.heartBeatPort))
 		}
 
 		if initp.networkInfo.heartBeatTLSPort != 0 {
-			confString = strings.ReplaceAll(confString, "heartbeat {",
-				fmt.Sprintf("heartbeat {\n        tls-mesh-seed-address-port %s %d", peer, initp.networkInfo.heartBeatTLSPort))
+			prependNetworkParam(conf, "heartbeat", "tls-mesh-seed-address-port",
+				fmt.Sprintf("%s %d", peer, initp.networkInfo.heartBeatTLSPort))
 		}
 	}
 
+	if err := fileScanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read peers: %v", err)
+	}
+
 	// If host networking is used force heartbeat and fabric to advertise network
 	// interface bound to K8s node's host network.
 	if initp.networkInfo.hostNetwork {
-		// 8 spaces, fixed in config writer file config manager lib
-		// TODO: The search pattern is not robust. Add a better marker in management lib.
 		if initp.networkInfo.heartBeatPort != 0 {
-			confString = strings.ReplaceAll(confString, "heartbeat {",
-				fmt.Sprintf("heartbeat {\n        address %s", initp.networkInfo.podIP))
+			prependNetworkParam(conf, "heartbeat", "address", initp.networkInfo.podIP)
 		}
 
 		if initp.networkInfo.heartBeatTLSPort != 0 {
-			confString = strings.ReplaceAll(confString, "heartbeat {",
-				fmt.Sprintf("heartbeat {\n        tls-address %s", initp.networkInfo.podIP))
+			prependNetworkParam(conf, "heartbeat", "tls-address", initp.networkInfo.podIP)
 		}
 
 		if initp.networkInfo.fabricPort != 0 {
-			confString = strings.ReplaceAll(confString, "fabric {",
-				fmt.Sprintf("fabric {\n        address %s", initp.networkInfo.podIP))
+			prependNetworkParam(conf, "fabric", "address", initp.networkInfo.podIP)
 		}
 
 		if initp.networkInfo.fabricTLSPort != 0 {
-			confString = strings.ReplaceAll(confString, "fabric {",
-				fmt.Sprintf("fabric {\n        tls-address %s", initp.networkInfo.podIP))
+			prependNetworkParam(conf, "fabric", "tls-address", initp.networkInfo.podIP)
 		}
 	}
 
 	// Update namespace sections with rack-id from pod annotation
-	confString = initp.updateNamespaceRackID(confString)
+	initp.updateNamespaceRackID(conf)
+
+	confString := conf.String()
 
 	// Remove escape sequence from LDAP configuration if any
 	confString = strings.ReplaceAll(confString, "$${_DNE}{un}", "${un}")
 	confString = strings.ReplaceAll(confString, "$${_DNE}{dn}", "${dn}")
 
-	if err = os.WriteFile(aerospikeConf, []byte(confString), 0644); err != nil { //nolint:gocritic,gosec // file permission
-		return err
-	}
+	return confString, nil
+}
 
-	if err := os.Remove(aerospikeTemplateConf); err != nil {
-		return err
+// prependNetworkParam adds a parameter at the beginning of the given network sub-context, if the context is present.
+func prependNetworkParam(conf *confEntry, context, name, value string) {
+	if section := conf.section("network", context); section != nil {
+		section.prependParam(name, value)
 	}
-
-	initp.logger.Info(fmt.Sprintf("Final aerospike conf file %s: \n%s", aerospikeConf, confString))
-
-	return nil
 }
 
 func (initp *InitParams) createAerospikeOpensslAndFipsCnf() error {
 	initp.logger.Info("Creating openssl.cnf and fips.cnf files")
 	//nolint:gocritic,gosec // file permission
-	if err := os.WriteFile(aerospikeOpensslCnf, opensslCnf, 0644); err != nil {
+	if err := os.WriteFile(initp.paths.aerospikeOpensslCnf(), opensslCnf, 0644); err != nil {
 		return fmt.Errorf("failed to write openssl.cnf: %v", err)
 	}
 
 	//nolint:gocritic,gosec // file permission
-	if err := os.WriteFile(aerospikeFipsCnf, fipsCnf, 0644); err != nil {
+	if err := os.WriteFile(initp.paths.aerospikeFipsCnf(), fipsCnf, 0644); err != nil {
 		return fmt.Errorf("failed to write fips.cnf: %v", err)
 	}
 
@@ -169,27 +203,29 @@ func (initp *InitParams) createAerospikeOpensslAndFipsCnf() error {
 // using the value from pod annotation "aerospike.com/override-rack-id"
 // Only proceeds if EnableRackIDOverride is set to true in AerospikeCluster CR spec
 // Only replaces rack-id if it exists in the template, does not add if missing
-func (initp *InitParams) updateNamespaceRackID(confString string) string {
+func (initp *InitParams) updateNamespaceRackID(conf *confEntry) {
 	// Check if dynamic rack-id is enabled in AerospikeCluster CR spec
 	if !asdbv1.GetBool(initp.aeroCluster.Spec.EnableRackIDOverride) {
 		initp.logger.Info("EnableRackIDOverride not set, skipping rack-id update")
-		return confString
+		return
 	}
 
 	initp.logger.Info("Updating namespace sections with override rack-id", "rack-id", initp.overrideRackID)
 
-	// Use regex to replace all rack-id values with the annotation value
-	rackIDPattern := regexp.MustCompile(`rack-id\s+\d+`)
-	result := rackIDPattern.ReplaceAllString(confString, fmt.Sprintf("rack-id    %d", initp.overrideRackID))
-
-	return result
+	for _, namespace := range conf.sections("namespace") {
+		for _, rackID := range namespace.params("rack-id") {
+			if _, err := strconv.Atoi(rackID.value); err == nil {
+				rackID.value = strconv.Itoa(initp.overrideRackID)
+			}
+		}
+	}
 }
 
 // Update access addresses in the configuration file
 // Compute the access endpoints based on network policy.
 // As a kludge the computed values are stored late to update node summary.
 func (initp *InitParams) substituteEndpoint(networkType asdbv1.AerospikeNetworkType,
-	addressType, configuredIP string, interfaceIPs []string, confString string) string {
+	addressType, configuredIP string, interfaceIPs []string, conf *confEntry) {
 	var (
 		accessAddress []string
 		accessPort    int32
@@ -197,24 +233,27 @@ func (initp *InitParams) substituteEndpoint(networkType asdbv1.AerospikeNetworkT
 
 	servicePort := initp.networkInfo.servicePort
 	mappedServicePort := initp.networkInfo.mappedServicePort
+	loadBalancerPort := initp.networkInfo.loadBalancerServicePort
+	family := initp.networkInfo.addressFamilies[addressType]
 
 	if addressType == tlsAccess || addressType == tlsAlternateAccess {
 		servicePort = initp.networkInfo.serviceTLSPort
 		mappedServicePort = initp.networkInfo.mappedServiceTLSPort
+		loadBalancerPort = initp.networkInfo.loadBalancerServiceTLSPort
 	}
 
 	//nolint:exhaustive // fallback to default
 	switch networkType {
 	case asdbv1.AerospikeNetworkTypePod:
-		accessAddress = append(accessAddress, initp.networkInfo.podIP)
+		accessAddress = initp.networkInfo.podIPs.addresses(family, initp.networkInfo.podIP)
 		accessPort = servicePort
 
 	case asdbv1.AerospikeNetworkTypeHostInternal:
-		accessAddress = append(accessAddress, initp.networkInfo.internalIP)
+		accessAddress = initp.networkInfo.internalIPs.addresses(family, initp.networkInfo.internalIP)
 		accessPort = mappedServicePort
 
 	case asdbv1.AerospikeNetworkTypeHostExternal:
-		accessAddress = append(accessAddress, initp.networkInfo.externalIP)
+		accessAddress = initp.networkInfo.externalIPs.addresses(family, initp.networkInfo.externalIP)
 		accessPort = mappedServicePort
 
 	case asdbv1.AerospikeNetworkTypeConfigured:
@@ -232,6 +271,18 @@ func (initp *InitParams) substituteEndpoint(networkType asdbv1.AerospikeNetworkT
 		accessAddress = interfaceIPs
 		accessPort = servicePort
 
+	case networkTypeDNSName:
+		accessAddress = append(accessAddress, initp.networkInfo.dnsNames[addressType])
+		accessPort = servicePort
+
+	case networkTypeLoadBalancer:
+		accessAddress = initp.networkInfo.loadBalancerAddresses
+		accessPort = loadBalancerPort
+
+		if accessPort == 0 {
+			accessPort = servicePort
+		}
+
 	default:
 		accessAddress = append(accessAddress, initp.networkInfo.podIP)
 		accessPort = servicePort
@@ -256,23 +307,19 @@ func (initp *InitParams) substituteEndpoint(networkType asdbv1.AerospikeNetworkT
 		initp.networkInfo.globalAddressesAndPorts.globalTLSAlternateAccessPort = accessPort
 	}
 
-	// Substitute in the configuration file.
-	// If multiple IPs are found, then add all of them in the specific addressType
-	var newStr string
-	for _, addr := range accessAddress {
-		newStr += fmt.Sprintf("%s-address    %s\n        ", addressType, addr)
+	service := conf.section("network", "service")
+	if service == nil {
+		return
 	}
 
-	confString = strings.ReplaceAll(confString, fmt.Sprintf("%s-address    <%s-address>", addressType, addressType),
-		strings.TrimSuffix(newStr, "\n        "))
-
-	re := regexp.MustCompile(fmt.Sprintf("\\s*%s-port\\s*%d", addressType, servicePort))
+	// Substitute in the configuration file.
+	// If multiple IPs are found, then add all of them in the specific addressType
+	service.replaceParam(addressType+"-address", fmt.Sprintf("<%s-address>", addressType), accessAddress)
 
-	if portString := re.FindString(confString); portString != "" {
-		// # This port is set in api/v1beta1/aerospikecluster_mutating_webhook.go and is used as placeholder.
-		confString = strings.ReplaceAll(confString, portString,
-			strings.ReplaceAll(portString, strconv.Itoa(int(servicePort)), strconv.Itoa(int(accessPort))))
+	// This port is set in api/v1beta1/aerospikecluster_mutating_webhook.go and is used as placeholder.
+	for _, port := range service.params(addressType + "-port") {
+		if port.value == strconv.Itoa(int(servicePort)) {
+			port.value = strconv.Itoa(int(accessPort))
+		}
 	}
-
-	return confString
 }
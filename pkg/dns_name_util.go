@@ -0,0 +1,136 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	asdbv1 "github.com/aerospike/aerospike-kubernetes-operator/v4/api/v1"
+)
+
+// networkTypeDNSName advertises a DNS name of the pod, from a pod or node annotation or a cluster wide template.
+// It is selected per access type with the network type annotations, see getAccessNetworkPolicy.
+const networkTypeDNSName asdbv1.AerospikeNetworkType = "dnsName"
+
+const (
+	// dnsNameAnnotationSuffix is appended to the access type, e.g. aerospike.com/access-dns-name,
+	// to form the pod or node annotation holding the DNS name of that access type.
+	dnsNameAnnotationSuffix = "-dns-name"
+	// dnsNameTemplateAnnotationSuffix forms the AerospikeCluster annotation holding the DNS name template.
+	dnsNameTemplateAnnotationSuffix = "-dns-name-template"
+
+	// defaultDNSNameTemplate is the name of the pod in the headless service of the cluster.
+	defaultDNSNameTemplate = "{{.PodName}}.{{.ClusterName}}.{{.Namespace}}.svc.{{.ClusterDomain}}"
+)
+
+// dnsNameTemplateData holds the fields available to the DNS name templates.
+type dnsNameTemplateData struct {
+	PodName     string
+	Namespace   string
+	ClusterName string
+	NodeName    string
+	RackID      int
+	// ClusterDomain is the DNS domain of the k8s cluster, set with --cluster-domain.
+	ClusterDomain string
+}
+
+// getDNSNames returns the DNS name of each access type using the dnsName network type. The name is read from the
+// pod annotation, then the node annotation, and is otherwise rendered from the AerospikeCluster annotation template
+// or the default template.
+func (initp *InitParams) getDNSNames(ctx context.Context, pod *corev1.Pod) (map[string]string, error) {
+	policy := initp.networkInfo.networkPolicy
+	networkTypes := map[string]asdbv1.AerospikeNetworkType{
+		access:             policy.AccessType,
+		alternateAccess:    policy.AlternateAccessType,
+		tlsAccess:          policy.TLSAccessType,
+		tlsAlternateAccess: policy.TLSAlternateAccessType,
+	}
+
+	var (
+		node    *corev1.Node
+		nodeErr error
+	)
+
+	dnsNames := make(map[string]string)
+
+	for addressType, networkType := range networkTypes {
+		if networkType != networkTypeDNSName {
+			continue
+		}
+
+		annotation := "aerospike.com/" + addressType + dnsNameAnnotationSuffix
+
+		name, ok := pod.Annotations[annotation]
+		if !ok {
+			if node == nil && nodeErr == nil {
+				node, nodeErr = getHostNode(ctx, initp.logger, initp.k8sClient, pod.Spec.NodeName,
+					initp.networkInfo.hostIP)
+			}
+
+			if nodeErr != nil {
+				return nil, nodeErr
+			}
+
+			if node != nil {
+				name, ok = node.Annotations[annotation]
+			}
+		}
+
+		if !ok {
+			var err error
+
+			if name, err = initp.renderDNSName(addressType, pod); err != nil {
+				return nil, err
+			}
+		}
+
+		name = strings.TrimSuffix(name, ".")
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) != 0 {
+			return nil, fmt.Errorf("invalid %s DNS name %q: %s", addressType, name, strings.Join(errs, ", "))
+		}
+
+		dnsNames[addressType] = name
+	}
+
+	if len(dnsNames) != 0 {
+		initp.logger.Info("Resolved access DNS names", "dns-names", dnsNames)
+	}
+
+	return dnsNames, nil
+}
+
+// renderDNSName renders the DNS name template of the access type set in the AerospikeCluster annotations,
+// or the default template.
+func (initp *InitParams) renderDNSName(addressType string, pod *corev1.Pod) (string, error) {
+	annotation := "aerospike.com/" + addressType + dnsNameTemplateAnnotationSuffix
+
+	text, ok := initp.aeroCluster.Annotations[annotation]
+	if !ok {
+		text = defaultDNSNameTemplate
+	}
+
+	tmpl, err := template.New(annotation).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse DNS name template %s: %v", annotation, err)
+	}
+
+	var buf bytes.Buffer
+
+	if err := tmpl.Execute(&buf, dnsNameTemplateData{
+		PodName:       pod.Name,
+		Namespace:     pod.Namespace,
+		ClusterName:   initp.aeroCluster.Name,
+		NodeName:      pod.Spec.NodeName,
+		RackID:        initp.rack.ID,
+		ClusterDomain: initp.network.ClusterDomain,
+	}); err != nil {
+		return "", fmt.Errorf("failed to render DNS name template %s: %v", annotation, err)
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,147 @@
+package pkg
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	asdbv1 "github.com/aerospike/aerospike-kubernetes-operator/v4/api/v1"
+)
+
+func TestGetDNSNames(t *testing.T) {
+	scheme, err := newScheme()
+	if err != nil {
+		t.Fatalf("newScheme() error = %v", err)
+	}
+
+	dnsNamePolicy := asdbv1.AerospikeNetworkPolicy{
+		AccessType:          networkTypeDNSName,
+		AlternateAccessType: asdbv1.AerospikeNetworkTypeHostExternal,
+	}
+
+	tests := []struct {
+		name               string
+		policy             asdbv1.AerospikeNetworkPolicy
+		clusterAnnotations map[string]string
+		podAnnotations     map[string]string
+		nodeAnnotations    map[string]string
+		want               map[string]string
+		wantErr            string
+	}{
+		{
+			name:   "default template",
+			policy: dnsNamePolicy,
+			want:   map[string]string{access: "aerospike-1-0.aerospike.ns.svc.cluster.example"},
+		},
+		{
+			name:   "every access type",
+			policy: asdbv1.AerospikeNetworkPolicy{AccessType: networkTypeDNSName, TLSAccessType: networkTypeDNSName},
+			clusterAnnotations: map[string]string{
+				"aerospike.com/tls-access-dns-name-template": "{{.PodName}}-tls.example.com",
+			},
+			want: map[string]string{
+				access:    "aerospike-1-0.aerospike.ns.svc.cluster.example",
+				tlsAccess: "aerospike-1-0-tls.example.com",
+			},
+		},
+		{
+			name:   "cluster template",
+			policy: dnsNamePolicy,
+			clusterAnnotations: map[string]string{
+				"aerospike.com/access-dns-name-template": "{{.PodName}}.rack{{.RackID}}.{{.NodeName}}.example.com",
+			},
+			want: map[string]string{access: "aerospike-1-0.rack1.node-1.example.com"},
+		},
+		{
+			name:   "node annotation",
+			policy: dnsNamePolicy,
+			clusterAnnotations: map[string]string{
+				"aerospike.com/access-dns-name-template": "{{.PodName}}.example.com",
+			},
+			nodeAnnotations: map[string]string{"aerospike.com/access-dns-name": "node-1.example.com"},
+			want:            map[string]string{access: "node-1.example.com"},
+		},
+		{
+			name:            "pod annotation",
+			policy:          dnsNamePolicy,
+			nodeAnnotations: map[string]string{"aerospike.com/access-dns-name": "node-1.example.com"},
+			podAnnotations:  map[string]string{"aerospike.com/access-dns-name": "pod.example.com."},
+			want:            map[string]string{access: "pod.example.com"},
+		},
+		{
+			name:   "not used",
+			policy: asdbv1.AerospikeNetworkPolicy{AccessType: asdbv1.AerospikeNetworkTypePod},
+			want:   map[string]string{},
+		},
+		{
+			name:           "invalid name",
+			policy:         dnsNamePolicy,
+			podAnnotations: map[string]string{"aerospike.com/access-dns-name": "Pod_1.example.com"},
+			wantErr:        `invalid access DNS name "Pod_1.example.com"`,
+		},
+		{
+			name:   "invalid template",
+			policy: dnsNamePolicy,
+			clusterAnnotations: map[string]string{
+				"aerospike.com/access-dns-name-template": "{{.PodName",
+			},
+			wantErr: "failed to parse DNS name template aerospike.com/access-dns-name-template",
+		},
+		{
+			name:   "unknown template field",
+			policy: dnsNamePolicy,
+			clusterAnnotations: map[string]string{
+				"aerospike.com/access-dns-name-template": "{{.Zone}}.example.com",
+			},
+			wantErr: "failed to render DNS name template aerospike.com/access-dns-name-template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1", Annotations: tt.nodeAnnotations},
+			}
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "aerospike-1-0", Namespace: "ns", Annotations: tt.podAnnotations},
+				Spec:       corev1.PodSpec{NodeName: "node-1"},
+			}
+
+			initp := &InitParams{
+				logger:    logr.Discard(),
+				k8sClient: fake.NewClientBuilder().WithScheme(scheme).WithObjects(node).Build(),
+				aeroCluster: &asdbv1.AerospikeCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "aerospike", Namespace: "ns",
+						Annotations: tt.clusterAnnotations},
+				},
+				rack:        &asdbv1.Rack{ID: 1},
+				network:     NetworkOptions{ClusterDomain: "cluster.example"},
+				networkInfo: &networkInfo{networkPolicy: tt.policy},
+			}
+
+			got, err := initp.getDNSNames(context.Background(), pod)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("getDNSNames() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("getDNSNames() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getDNSNames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
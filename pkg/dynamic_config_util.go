@@ -0,0 +1,320 @@
+package pkg
+
+import (
+	goctx "context"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	aero "github.com/aerospike/aerospike-client-go/v8"
+	"github.com/aerospike/aerospike-management-lib/asconfig"
+	"github.com/go-logr/logr"
+
+	asdbv1 "github.com/aerospike/aerospike-kubernetes-operator/v4/api/v1"
+	"github.com/aerospike/aerospike-kubernetes-operator/v4/pkg/configschema"
+	"github.com/aerospike/aerospike-kubernetes-operator/v4/pkg/utils"
+)
+
+const dynamicConfigResultAnnotation = "aerospike.com/dynamic-config-result"
+
+// Results of a dynamic config change.
+const (
+	dynamicConfigApplied = "applied"
+	dynamicConfigFailed  = "failed"
+	dynamicConfigSkipped = "skipped"
+)
+
+// dynamicConfigResult is the outcome of a single set-config command as published in the pod annotation.
+type dynamicConfigResult struct {
+	Command string `json:"command"`
+	Result  string `json:"result"`
+	Error   string `json:"error,omitempty"`
+}
+
+var (
+	configSchemaOnce sync.Once
+	errConfigSchema  error
+)
+
+// initConfigSchema loads the Aerospike config schemas shipped with the operator into the management lib,
+// which tells the dynamic parameters and their set-config commands apart per server version.
+func initConfigSchema(logger logr.Logger) error {
+	configSchemaOnce.Do(func() {
+		schemaMap, err := configschema.NewSchemaMap()
+		if err != nil {
+			errConfigSchema = fmt.Errorf("failed to load aerospike config schemas: %v", err)
+			return
+		}
+
+		asconfig.InitFromMap(logger, schemaMap)
+	})
+
+	return errConfigSchema
+}
+
+// infoConn sends the info commands of the management lib to the local Aerospike server.
+type infoConn struct {
+	ctx     goctx.Context
+	policy  *aero.ClientPolicy
+	address string
+}
+
+func (c *infoConn) RunInfo(_ *aero.ClientPolicy, commands ...string) (map[string]string, error) {
+	return requestInfo(c.ctx, c.policy, c.address, commands...)
+}
+
+// flatConf parses aerospike.conf into the flat map of the management lib, keyed by the section path of each parameter
+// in the format of the AerospikeCluster aerospikeConfig, e.g. namespaces.{test}.default-ttl.
+func (initp *InitParams) flatConf(data []byte) (asconfig.Conf, error) {
+	conf, err := asconfig.NewASConfigFromBytes(initp.logger, data, asconfig.AeroConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return *conf.GetFlatMap(), nil
+}
+
+// diffDynamicConfig returns the changes between two aerospike.conf versions of the given server build,
+// and the changed parameters which can not be applied at runtime.
+func (initp *InitParams) diffDynamicConfig(oldData, newData []byte, build string) (asconfig.DynamicConfigMap,
+	[]string, error) {
+	oldConf, err := initp.flatConf(oldData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse current aerospike conf: %v", err)
+	}
+
+	newConf, err := initp.flatConf(newData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse new aerospike conf: %v", err)
+	}
+
+	changes, err := asconfig.ConfDiff(initp.logger, newConf, oldConf, true, build)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to diff aerospike conf: %v", err)
+	}
+
+	// The diff leaves out the node specific parameters e.g. addresses and devices, these need a restart.
+	staticChanges := nodeSpecificChanges(oldConf, newConf, changes)
+
+	dynamic, err := asconfig.IsAllDynamicConfig(initp.logger, changes, build)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !dynamic {
+		for key := range changes {
+			staticChanges = append(staticChanges, key)
+		}
+	}
+
+	sort.Strings(staticChanges)
+
+	return changes, staticChanges, nil
+}
+
+// nodeSpecificChanges returns the keys of the parameters which differ between the two flat configs
+// and are not part of the diff. Reordered sections are ignored.
+func nodeSpecificChanges(oldConf, newConf asconfig.Conf, changes asconfig.DynamicConfigMap) []string {
+	var keys []string
+
+	check := func(key string) {
+		if _, ok := changes[key]; ok || asconfig.BaseKey(key) == "<index>" || utils.ContainsString(keys, key) {
+			return
+		}
+
+		if !reflect.DeepEqual(oldConf[key], newConf[key]) {
+			keys = append(keys, key)
+		}
+	}
+
+	for key := range oldConf {
+		check(key)
+	}
+
+	for key := range newConf {
+		check(key)
+	}
+
+	return keys
+}
+
+// applyConfDynamically renders the new aerospike.conf and applies the changed parameters to the running
+// Aerospike server with set-config. The server is restarted only if a static parameter changed.
+func (initp *InitParams) applyConfDynamically(ctx goctx.Context) error {
+	oldData, err := os.ReadFile(initp.paths.aerospikeConf())
+	if err != nil {
+		return err
+	}
+
+	// Keep the config the server is running with to roll back to if a restart is needed and fails.
+	lastGoodBackup, err := initp.lastGoodConfBackup()
+	if err != nil {
+		return fmt.Errorf("failed to backup aerospike conf: %v", err)
+	}
+
+	if err := initp.copyTemplates(initp.paths.configMapDir(), initp.paths.ConfigVolume); err != nil {
+		return err
+	}
+
+	if err := initp.createAerospikeConf(ctx); err != nil {
+		return err
+	}
+
+	newData, err := os.ReadFile(initp.paths.aerospikeConf())
+	if err != nil {
+		return err
+	}
+
+	address := initp.infoAddress()
+
+	var (
+		policy        *aero.ClientPolicy
+		changes       asconfig.DynamicConfigMap
+		staticChanges []string
+		commands      []string
+	)
+
+	if address != "" {
+		if policy, err = initp.infoClientPolicy(ctx); err == nil {
+			changes, staticChanges, commands, err = initp.dynamicConfigCommands(ctx, policy, address, oldData,
+				newData)
+		}
+
+		if err != nil {
+			// The changes are applied with a restart instead.
+			initp.logger.Error(err, "Failed to compute dynamic config changes")
+
+			staticChanges = append(staticChanges, err.Error())
+		}
+	}
+
+	switch {
+	case address != "" && len(staticChanges) == 0 && len(changes) == 0:
+		initp.logger.Info("No aerospike conf change to apply")
+		return nil
+
+	case len(staticChanges) != 0 || address == "":
+		initp.logger.Info("Aerospike conf changes need a restart", "static-changes", staticChanges,
+			"info-address", address)
+
+		if err := initp.checkWarmRestartSupported(); err != nil {
+			return err
+		}
+
+		return initp.restartASDWithRollback(ctx, lastGoodBackup)
+	}
+
+	results := make([]dynamicConfigResult, len(commands))
+	applied := 0
+
+	for idx := range commands {
+		// Commands hold the resolved secret values, only their redacted form is published.
+		results[idx] = dynamicConfigResult{
+			Command: initp.redactCommand(commands[idx]),
+			Result:  dynamicConfigSkipped,
+		}
+
+		// Stop at the first failure, the remaining changes are left to a restart by the operator.
+		if applied < idx {
+			continue
+		}
+
+		if err := initp.setConfig(ctx, policy, address, commands[idx], results[idx].Command); err != nil {
+			results[idx].Result = dynamicConfigFailed
+			results[idx].Error = err.Error()
+
+			initp.recorder.warning(ctx, reasonDynamicConfigFailed, "Failed to apply %s: %v", results[idx].Command,
+				err)
+
+			continue
+		}
+
+		results[idx].Result = dynamicConfigApplied
+		applied++
+
+		initp.recorder.normal(ctx, reasonDynamicConfigApplied, "Applied %s", results[idx].Command)
+	}
+
+	switch {
+	case applied == len(commands):
+		initp.dynamicConfigStatus = asdbv1.Empty
+	case applied == 0:
+		initp.dynamicConfigStatus = asdbv1.Failed
+	default:
+		initp.dynamicConfigStatus = asdbv1.PartiallyFailed
+	}
+
+	initp.logger.Info("Applied aerospike conf changes dynamically", "results", results,
+		"status", initp.dynamicConfigStatus)
+
+	if err := initp.patchPodAnnotation(ctx, dynamicConfigResultAnnotation, results); err != nil {
+		initp.logger.Error(err, "Failed to publish dynamic config result", "annotation", dynamicConfigResultAnnotation)
+	}
+
+	// After a failure the server runs with part of the new aerospike.conf. The conf is kept and the pod status
+	// reports the failure without the new config hash, the next quick restart restarts the server.
+	return nil
+}
+
+// dynamicConfigCommands returns the changes between the two aerospike.conf versions for the build of the running
+// server, the changed parameters which need a restart and the set-config commands applying the others.
+func (initp *InitParams) dynamicConfigCommands(ctx goctx.Context, policy *aero.ClientPolicy, address string,
+	oldData, newData []byte) (changes asconfig.DynamicConfigMap, staticChanges, commands []string, err error) {
+	if err := initConfigSchema(initp.logger); err != nil {
+		return nil, nil, nil, err
+	}
+
+	info, err := requestInfo(ctx, policy, address, infoCommandBuild)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get Aerospike server build: %v", err)
+	}
+
+	build := info[infoCommandBuild]
+
+	changes, staticChanges, err = initp.diffDynamicConfig(oldData, newData, build)
+	if err != nil || len(staticChanges) != 0 || len(changes) == 0 {
+		return changes, staticChanges, nil, err
+	}
+
+	commands, err = asconfig.CreateSetConfigCmdListWithBuildVersion(initp.logger, changes,
+		&infoConn{ctx: ctx, policy: policy, address: address}, nil, build)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to create set-config commands: %v", err)
+	}
+
+	return changes, nil, commands, nil
+}
+
+// setConfig sends a set-config info command to the Aerospike server. redactedCommand is logged instead.
+func (initp *InitParams) setConfig(ctx goctx.Context, policy *aero.ClientPolicy, address, command,
+	redactedCommand string) error {
+	info, err := requestInfo(ctx, policy, address, command)
+	if err != nil {
+		return err
+	}
+
+	if result := info[command]; result != "ok" {
+		return fmt.Errorf("set-config returned %q", result)
+	}
+
+	initp.logger.Info("Applied dynamic config", "command", redactedCommand)
+
+	return nil
+}
+
+// redactCommand masks the values of the sensitive parameters and the resolved secret values in a set-config command.
+func (initp *InitParams) redactCommand(command string) string {
+	params := strings.Split(command, ";")
+	keys := initp.redactedKeys()
+
+	for idx, param := range params {
+		if name, _, ok := strings.Cut(param, "="); ok && utils.ContainsString(keys, name) {
+			params[idx] = name + "=" + redactedValue
+		}
+	}
+
+	return initp.redactSecretValues(strings.Join(params, ";"))
+}
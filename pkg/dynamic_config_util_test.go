@@ -0,0 +1,262 @@
+package pkg
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/aerospike/aerospike-management-lib/asconfig"
+	"github.com/go-logr/logr"
+)
+
+// testConfigSchema is a minimal Aerospike 7.0.0 config schema with a few dynamic and static parameters.
+const testConfigSchema = `{
+  "type": "object",
+  "properties": {
+    "service": {
+      "type": "object",
+      "properties": {
+        "proto-fd-max": {"type": "integer", "default": 15000, "dynamic": true},
+        "cluster-name": {"type": "string", "default": "", "dynamic": false}
+      }
+    },
+    "network": {
+      "type": "object",
+      "properties": {
+        "service": {
+          "type": "object",
+          "properties": {
+            "port": {"type": "integer", "default": 3000, "dynamic": false},
+            "access-addresses": {"type": "array", "items": {"type": "string"}, "dynamic": false}
+          }
+        }
+      }
+    },
+    "namespaces": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string", "dynamic": false},
+          "default-ttl": {"type": "integer", "default": 0, "dynamic": true},
+          "memory-size": {"type": "integer", "default": 0, "dynamic": false}
+        }
+      }
+    }
+  }
+}`
+
+const testBuild = "7.0.0.1"
+
+// testConf returns an aerospike.conf with the given values.
+func testConf(protoFdMax, clusterName, accessAddress, defaultTTL string) []byte {
+	return []byte("service {\n" +
+		"    proto-fd-max " + protoFdMax + "\n" +
+		"    cluster-name " + clusterName + "\n" +
+		"}\n" +
+		"network {\n" +
+		"    service {\n" +
+		"        port 3000\n" +
+		"        access-address " + accessAddress + "\n" +
+		"    }\n" +
+		"}\n" +
+		"namespace test {\n" +
+		"    default-ttl " + defaultTTL + "\n" +
+		"    memory-size 1073741824\n" +
+		"}\n")
+}
+
+func TestDiffDynamicConfig(t *testing.T) {
+	asconfig.InitFromMap(logr.Discard(), map[string]string{"7.0.0": testConfigSchema})
+
+	initp := &InitParams{logger: logr.Discard()}
+	oldConf := testConf("1000", "test", "10.0.0.1", "0")
+
+	tests := []struct {
+		name              string
+		newConf           []byte
+		wantStaticChanges []string
+		wantCommands      []string
+	}{
+		{
+			name:    "unchanged",
+			newConf: oldConf,
+		},
+		{
+			name:    "dynamic changes",
+			newConf: testConf("2000", "test", "10.0.0.1", "10"),
+			wantCommands: []string{
+				"set-config:context=namespace;id=test;default-ttl=10",
+				"set-config:context=service;proto-fd-max=2000",
+			},
+		},
+		{
+			name:              "static change",
+			newConf:           testConf("2000", "prod", "10.0.0.1", "0"),
+			wantStaticChanges: []string{"service.cluster-name", "service.proto-fd-max"},
+		},
+		{
+			name:              "node specific change",
+			newConf:           testConf("1000", "test", "10.0.0.2", "0"),
+			wantStaticChanges: []string{"network.service.access-addresses"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, staticChanges, err := initp.diffDynamicConfig(oldConf, tt.newConf, testBuild)
+			if err != nil {
+				t.Fatalf("diffDynamicConfig() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(staticChanges, tt.wantStaticChanges) {
+				t.Errorf("diffDynamicConfig() static changes = %v, want %v", staticChanges, tt.wantStaticChanges)
+			}
+
+			if len(staticChanges) != 0 {
+				return
+			}
+
+			// No info connection is needed without logging changes.
+			commands, err := asconfig.CreateSetConfigCmdListWithBuildVersion(initp.logger, changes, nil, nil,
+				testBuild)
+			if err != nil {
+				t.Fatalf("CreateSetConfigCmdListWithBuildVersion() error = %v", err)
+			}
+
+			sort.Strings(commands)
+
+			if len(commands) == 0 {
+				commands = nil
+			}
+
+			if !reflect.DeepEqual(commands, tt.wantCommands) {
+				t.Errorf("set-config commands = %v, want %v", commands, tt.wantCommands)
+			}
+		})
+	}
+}
+
+func TestDiffDynamicConfigUnsupportedVersion(t *testing.T) {
+	asconfig.InitFromMap(logr.Discard(), map[string]string{"7.0.0": testConfigSchema})
+
+	initp := &InitParams{logger: logr.Discard()}
+
+	_, _, err := initp.diffDynamicConfig(testConf("1000", "test", "10.0.0.1", "0"),
+		testConf("2000", "test", "10.0.0.1", "0"), "6.4.0.1")
+	if err == nil {
+		t.Error("diffDynamicConfig() for a version without schema returned no error")
+	}
+}
+
+func TestFlatConf(t *testing.T) {
+	initp := &InitParams{logger: logr.Discard()}
+
+	conf, err := initp.flatConf(testConf("1000", "test", "10.0.0.1", "0"))
+	if err != nil {
+		t.Fatalf("flatConf() error = %v", err)
+	}
+
+	for _, key := range []string{
+		"service.proto-fd-max",
+		"service.cluster-name",
+		"network.service.access-addresses",
+		"namespaces.{test}.default-ttl",
+	} {
+		if _, ok := conf[key]; !ok {
+			keys := make([]string, 0, len(conf))
+			for k := range conf {
+				keys = append(keys, k)
+			}
+
+			t.Errorf("flatConf() has no key %s, keys: %s", key, strings.Join(keys, ", "))
+		}
+	}
+}
+
+func TestNodeSpecificChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldConf asconfig.Conf
+		newConf asconfig.Conf
+		changes asconfig.DynamicConfigMap
+		want    []string
+	}{
+		{
+			name:    "unchanged",
+			oldConf: asconfig.Conf{"network.service.access-addresses": []string{"10.0.0.1"}},
+			newConf: asconfig.Conf{"network.service.access-addresses": []string{"10.0.0.1"}},
+		},
+		{
+			name:    "changed node specific parameter",
+			oldConf: asconfig.Conf{"network.service.access-addresses": []string{"10.0.0.1"}},
+			newConf: asconfig.Conf{"network.service.access-addresses": []string{"10.0.0.2"}},
+			want:    []string{"network.service.access-addresses"},
+		},
+		{
+			name:    "added node specific parameter",
+			oldConf: asconfig.Conf{},
+			newConf: asconfig.Conf{"network.service.access-addresses": []string{"10.0.0.2"}},
+			want:    []string{"network.service.access-addresses"},
+		},
+		{
+			name:    "change in diff",
+			oldConf: asconfig.Conf{"service.proto-fd-max": 1000},
+			newConf: asconfig.Conf{"service.proto-fd-max": 2000},
+			changes: asconfig.DynamicConfigMap{
+				"service.proto-fd-max": {asconfig.Update: 2000},
+			},
+		},
+		{
+			name:    "reordered section",
+			oldConf: asconfig.Conf{"namespaces.{test}.<index>": 0},
+			newConf: asconfig.Conf{"namespaces.{test}.<index>": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nodeSpecificChanges(tt.oldConf, tt.newConf, tt.changes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nodeSpecificChanges() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactCommand(t *testing.T) {
+	initp := &InitParams{
+		redactKeys:   []string{"auth-user"},
+		secretValues: []string{"s3cr3t"},
+	}
+
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{
+			command: "set-config:context=service;proto-fd-max=2000",
+			want:    "set-config:context=service;proto-fd-max=2000",
+		},
+		{
+			command: "set-config:context=xdr;dc=dc1;auth-user=admin",
+			want:    "set-config:context=xdr;dc=dc1;auth-user=" + redactedValue,
+		},
+		{
+			command: "set-config:context=network;tls-name=tls1;key-file-password=password",
+			want:    "set-config:context=network;tls-name=tls1;key-file-password=" + redactedValue,
+		},
+		{
+			command: "set-config:context=xdr;dc=dc1;node-address-port=s3cr3t:3000",
+			want:    "set-config:context=xdr;dc=dc1;node-address-port=" + redactedValue + ":3000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			if got := initp.redactCommand(tt.command); got != tt.want {
+				t.Errorf("redactCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
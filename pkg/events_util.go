@@ -0,0 +1,140 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	asdbv1 "github.com/aerospike/aerospike-kubernetes-operator/v4/api/v1"
+)
+
+const eventSourceComponent = "aerospike-init"
+
+// Event reasons recorded by the init container.
+const (
+	reasonPhaseStarted             = "PhaseStarted"
+	reasonPhaseCompleted           = "PhaseCompleted"
+	reasonPhaseFailed              = "PhaseFailed"
+	reasonNodeIdentity             = "NodeIdentity"
+	reasonVolumeInitStarted        = "VolumeInitStarted"
+	reasonVolumeInitCompleted      = "VolumeInitCompleted"
+	reasonVolumeInitFailed         = "VolumeInitFailed"
+	reasonVolumeWipeStarted        = "VolumeWipeStarted"
+	reasonVolumeWipeProgress       = "VolumeWipeProgress"
+	reasonVolumeWipeCompleted      = "VolumeWipeCompleted"
+	reasonVolumeWipeInterrupted    = "VolumeWipeInterrupted"
+	reasonVolumeWipeSkipped        = "VolumeWipeSkipped"
+	reasonVolumeWipeFailed         = "VolumeWipeFailed"
+	reasonDirtyVolumeCleanup       = "DirtyVolumeCleanup"
+	reasonDirtyVolumeCleanupFailed = "DirtyVolumeCleanupFailed"
+	reasonWarmRestart              = "WarmRestart"
+	reasonWarmRestartFailed        = "WarmRestartFailed"
+	reasonRestartSkipped           = "RestartSkipped"
+	reasonConfRolledBack           = "ConfRolledBack"
+	reasonDynamicConfigApplied     = "DynamicConfigApplied"
+	reasonDynamicConfigFailed      = "DynamicConfigFailed"
+	reasonStatusUpdated            = "PodStatusUpdated"
+	reasonStatusUpdateFailed       = "PodStatusUpdateFailed"
+)
+
+// eventRecorder records k8s Events against the pod and the AerospikeCluster it belongs to,
+// so that the decisions taken by the init container can be audited with kubectl get events.
+// Failing to record an event is logged and never fails the init.
+type eventRecorder struct {
+	k8sClient client.Client
+	logger    logr.Logger
+	pod       corev1.ObjectReference
+	cluster   corev1.ObjectReference
+}
+
+func newEventRecorder(k8sClient client.Client, logger logr.Logger, podName, namespace string,
+	aeroCluster *asdbv1.AerospikeCluster) *eventRecorder {
+	return &eventRecorder{
+		k8sClient: k8sClient,
+		logger:    logger,
+		pod: corev1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "Pod",
+			Name:       podName,
+			Namespace:  namespace,
+		},
+		cluster: corev1.ObjectReference{
+			APIVersion: asdbv1.GroupVersion.String(),
+			Kind:       "AerospikeCluster",
+			Name:       aeroCluster.Name,
+			Namespace:  aeroCluster.Namespace,
+			UID:        aeroCluster.UID,
+		},
+	}
+}
+
+// setPodUID sets the UID of the pod events are recorded against, once the pod has been fetched.
+func (r *eventRecorder) setPodUID(uid types.UID) {
+	r.pod.UID = uid
+}
+
+// normal records a Normal event against the pod and the AerospikeCluster.
+func (r *eventRecorder) normal(ctx context.Context, reason, messageFmt string, args ...interface{}) {
+	r.record(ctx, corev1.EventTypeNormal, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// warning records a Warning event against the pod and the AerospikeCluster.
+func (r *eventRecorder) warning(ctx context.Context, reason, messageFmt string, args ...interface{}) {
+	r.record(ctx, corev1.EventTypeWarning, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *eventRecorder) record(ctx context.Context, eventType, reason, message string) {
+	r.podEvent(ctx, eventType, reason, message)
+	// Cluster events are shared by all the pods, name the pod in the message.
+	r.create(ctx, &r.cluster, eventType, reason, fmt.Sprintf("Pod %s: %s", r.pod.Name, message))
+}
+
+// podEvent records an event against the pod only.
+func (r *eventRecorder) podEvent(ctx context.Context, eventType, reason, message string) {
+	r.create(ctx, &r.pod, eventType, reason, message)
+}
+
+func (r *eventRecorder) create(ctx context.Context, ref *corev1.ObjectReference, eventType, reason,
+	message string) {
+	now := metav1.NewTime(time.Now())
+
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: ref.Name + ".",
+			Namespace:    ref.Namespace,
+		},
+		InvolvedObject: *ref,
+		Reason:         reason,
+		Message:        message,
+		Type:           eventType,
+		Source:         corev1.EventSource{Component: eventSourceComponent},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+
+	if err := r.k8sClient.Create(ctx, event); err != nil {
+		r.logger.Error(err, "Failed to record event", "kind", ref.Kind, "reason", reason, "message", message)
+	}
+}
+
+// phaseStarted records the start of an init phase e.g. ColdRestart.
+func (r *eventRecorder) phaseStarted(ctx context.Context, phase string) {
+	r.normal(ctx, reasonPhaseStarted, "%s started", phase)
+}
+
+// phaseFinished records the outcome of an init phase.
+func (r *eventRecorder) phaseFinished(ctx context.Context, phase string, err error) {
+	if err != nil {
+		r.warning(ctx, reasonPhaseFailed, "%s failed: %v", phase, err)
+		return
+	}
+
+	r.normal(ctx, reasonPhaseCompleted, "%s completed", phase)
+}
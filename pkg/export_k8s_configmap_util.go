@@ -2,63 +2,129 @@ package pkg
 
 import (
 	"context"
-	"os"
-	"path/filepath"
+	"fmt"
+	"time"
 
+	"github.com/aerospike/aerospike-management-lib/asconfig"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/aerospike/aerospike-kubernetes-operator/v4/pkg/utils"
+)
+
+const (
+	confHashKey = "aerospikeConfHash"
+
+	configMapPollInterval    = time.Second
+	configMapMaxPollInterval = 30 * time.Second
 )
 
 func (initp *InitParams) ExportK8sConfigmap(ctx context.Context, namespace, cmName, toDir string) error {
-	configMap := &corev1.ConfigMap{}
-	if err := initp.k8sClient.Get(ctx,
-		types.NamespacedName{Name: cmName, Namespace: namespace}, configMap); err != nil {
+	configMap, err := initp.getExpectedConfigMap(ctx, namespace, cmName)
+	if err != nil {
 		return err
 	}
 
-	if err := os.MkdirAll(toDir, 0755); err != nil { //nolint:gocritic // file permission
-		return err
-	}
+	files := make(map[string][]byte, len(configMap.Data)+len(configMap.BinaryData))
 
 	for key, value := range configMap.Data {
-		f, err := os.Create(filepath.Join(toDir, key))
-		if err != nil {
-			return err
-		}
+		files[key] = []byte(value)
+	}
 
-		if _, err = f.WriteString(value); err != nil {
-			return err
-		}
+	for key, value := range configMap.BinaryData {
+		files[key] = value
+	}
+
+	if err := writeAtomicDir(initp.logger, toDir, files); err != nil {
+		return fmt.Errorf("failed to export configmap %s to %s: %v", cmName, toDir, err)
+	}
+
+	initp.logger.Info("Created and populated config map directory", "cm-name", cmName, "dir", toDir)
+
+	return nil
+}
 
-		if err := f.Sync(); err != nil {
-			return err
+// getExpectedConfigMap gets the configmap and polls it with backoff until it holds the expected aerospikeConfHash,
+// so that a stale revision is never exported. The expected hash is the configured one, else the one of the rack
+// in the AerospikeCluster spec. Only transient errors are retried while polling.
+func (initp *InitParams) getExpectedConfigMap(ctx context.Context, namespace, cmName string) (*corev1.ConfigMap,
+	error) {
+	configMap := &corev1.ConfigMap{}
+	namespacedName := types.NamespacedName{Name: cmName, Namespace: namespace}
+	expectedHash := initp.configMap.ExpectedConfHash
+
+	if expectedHash == "" {
+		var err error
+
+		if expectedHash, err = initp.specConfHash(); err != nil {
+			return nil, err
 		}
+	}
 
-		if err := f.Close(); err != nil {
-			return err
+	if expectedHash == "" {
+		if err := initp.k8sClient.Get(ctx, namespacedName, configMap); err != nil {
+			return nil, err
 		}
+
+		return configMap, nil
 	}
 
-	for key, value := range configMap.BinaryData {
-		f, err := os.Create(filepath.Join(toDir, key))
-		if err != nil {
-			return err
+	pollCtx, cancel := context.WithTimeout(ctx, initp.configMap.WaitTimeout)
+	defer cancel()
+
+	var lastErr error
+
+	delay := configMapPollInterval
+
+	for {
+		err := initp.k8sClient.Get(pollCtx, namespacedName, configMap)
+
+		switch hash := configMap.Data[confHashKey]; {
+		case err != nil && !isTransientError(err) && pollCtx.Err() == nil:
+			return nil, fmt.Errorf("failed to get configmap %s/%s: %v", namespace, cmName, err)
+		case err != nil:
+			lastErr = err
+			initp.logger.Error(err, "Failed to get configmap, retrying", "cm-name", cmName)
+		case hash != expectedHash:
+			lastErr = fmt.Errorf("configmap %s has %s %q", cmName, confHashKey, hash)
+			initp.logger.Info("Waiting for configmap to reach expected config hash", "cm-name", cmName,
+				"expected", expectedHash, "current", hash)
+		default:
+			initp.logger.Info("Configmap matches expected config hash", "cm-name", cmName, "hash", expectedHash)
+			return configMap, nil
 		}
 
-		if _, err = f.Write(value); err != nil {
-			return err
+		select {
+		case <-pollCtx.Done():
+			return nil, fmt.Errorf("configmap %s/%s did not reach %s %q within %s: %v", namespace, cmName,
+				confHashKey, expectedHash, initp.configMap.WaitTimeout, lastErr)
+		case <-time.After(wait.Jitter(delay, 0.1)):
 		}
 
-		if err := f.Sync(); err != nil {
-			return err
+		delay = min(2*delay, configMapMaxPollInterval)
+	}
+}
+
+// specConfHash returns the aerospikeConfHash of the configmap of the pod rack in the AerospikeCluster spec,
+// the hash of the aerospike.conf template the operator renders from the rack aerospikeConfig.
+// Empty if the rack revision of the pod is no longer in the spec.
+func (initp *InitParams) specConfHash() (string, error) {
+	racks := initp.aeroCluster.Spec.RackConfig.Racks
+
+	for idx := range racks {
+		rack := &racks[idx]
+		if rack.ID != initp.rack.ID || rack.Revision != initp.rack.Revision {
+			continue
 		}
 
-		if err := f.Close(); err != nil {
-			return err
+		conf, err := asconfig.NewMapAsConfig(initp.logger, rack.AerospikeConfig.Value)
+		if err != nil {
+			return "", fmt.Errorf("failed to build aerospike conf template of rack %d: %v", rack.ID, err)
 		}
-	}
 
-	initp.logger.Info("Created and populated config map directory", "cm-name", cmName, "dir", toDir)
+		return utils.GetHash(string(conf.ToConfFile()))
+	}
 
-	return nil
+	return "", nil
 }
@@ -0,0 +1,183 @@
+package pkg
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	asdbv1 "github.com/aerospike/aerospike-kubernetes-operator/v4/api/v1"
+)
+
+func TestGetExpectedConfigMap(t *testing.T) {
+	scheme, err := newScheme()
+	if err != nil {
+		t.Fatalf("newScheme() error = %v", err)
+	}
+
+	specRack := asdbv1.Rack{
+		ID: 1,
+		AerospikeConfig: asdbv1.AerospikeConfigSpec{
+			Value: map[string]interface{}{
+				"service": map[string]interface{}{"cluster-name": "test"},
+			},
+		},
+	}
+
+	aeroCluster := &asdbv1.AerospikeCluster{
+		Spec: asdbv1.AerospikeClusterSpec{
+			RackConfig: asdbv1.RackConfig{Racks: []asdbv1.Rack{specRack}},
+		},
+	}
+
+	specHash, err := (&InitParams{logger: logr.Discard(), aeroCluster: aeroCluster, rack: &specRack}).specConfHash()
+	if err != nil || specHash == "" {
+		t.Fatalf("specConfHash() = %q, %v", specHash, err)
+	}
+
+	errServerTimeout := apierrors.NewServerTimeout(schema.GroupResource{Resource: "configmaps"}, "get", 1)
+	errNotFound := apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "aerospike-1")
+
+	tests := []struct {
+		name         string
+		expectedHash string
+		// rack is the rack of the pod, the rack in the spec if nil.
+		rack *asdbv1.Rack
+		// cmHash is the aerospikeConfHash held by the configmap.
+		cmHash string
+		// getErrs are returned by the first gets, the next staleGets return the configmap with a stale hash.
+		getErrs   []error
+		staleGets int
+		wantGets  int
+		wantErr   string
+	}{
+		{
+			name:         "configured hash",
+			expectedHash: "hash-1",
+			cmHash:       "hash-1",
+			wantGets:     1,
+		},
+		{
+			name:         "configured hash after stale revision",
+			expectedHash: "hash-1",
+			cmHash:       "hash-1",
+			staleGets:    1,
+			wantGets:     2,
+		},
+		{
+			name:         "transient error retried",
+			expectedHash: "hash-1",
+			cmHash:       "hash-1",
+			getErrs:      []error{errServerTimeout},
+			wantGets:     2,
+		},
+		{
+			name:         "non-transient error",
+			expectedHash: "hash-1",
+			cmHash:       "hash-1",
+			getErrs:      []error{errNotFound},
+			wantGets:     1,
+			wantErr:      "failed to get configmap",
+		},
+		{
+			name:         "timeout",
+			expectedHash: "hash-2",
+			cmHash:       "hash-1",
+			wantErr:      `did not reach aerospikeConfHash "hash-2"`,
+		},
+		{
+			name:     "spec hash",
+			cmHash:   specHash,
+			wantGets: 1,
+		},
+		{
+			name:    "spec hash timeout",
+			cmHash:  "hash-1",
+			wantErr: "did not reach aerospikeConfHash " + `"` + specHash + `"`,
+		},
+		{
+			name:     "rack revision not in spec",
+			rack:     &asdbv1.Rack{ID: 1, Revision: "old"},
+			cmHash:   "hash-1",
+			wantGets: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "aerospike-1", Namespace: "aerospike"},
+				Data:       map[string]string{confHashKey: tt.cmHash},
+			}
+
+			gets := 0
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object,
+						opts ...client.GetOption) error {
+						gets++
+
+						if gets <= len(tt.getErrs) {
+							return tt.getErrs[gets-1]
+						}
+
+						if err := c.Get(ctx, key, obj, opts...); err != nil {
+							return err
+						}
+
+						if gets <= len(tt.getErrs)+tt.staleGets {
+							obj.(*corev1.ConfigMap).Data[confHashKey] = "stale"
+						}
+
+						return nil
+					},
+				}).Build()
+
+			rack := tt.rack
+			if rack == nil {
+				rack = &specRack
+			}
+
+			initp := &InitParams{
+				logger:      logr.Discard(),
+				k8sClient:   k8sClient,
+				aeroCluster: aeroCluster,
+				rack:        rack,
+				configMap: ConfigMapOptions{
+					ExpectedConfHash: tt.expectedHash,
+					WaitTimeout:      3 * time.Second,
+				},
+			}
+
+			got, err := initp.getExpectedConfigMap(context.Background(), "aerospike", "aerospike-1")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("getExpectedConfigMap() error = %v, want %q", err, tt.wantErr)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("getExpectedConfigMap() error = %v", err)
+				}
+
+				if got.Data[confHashKey] != tt.cmHash {
+					t.Errorf("getExpectedConfigMap() returned %s %q, want %q", confHashKey, got.Data[confHashKey],
+						tt.cmHash)
+				}
+			}
+
+			if tt.wantGets != 0 && gets != tt.wantGets {
+				t.Errorf("getExpectedConfigMap() got the configmap %d times, want %d", gets, tt.wantGets)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package pkg
+
+import (
+	goctx "context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// terminationMessageMaxSize is the size limit of a container termination message enforced by kubelet.
+const terminationMessageMaxSize = 4096
+
+// InitReport is the machine-readable outcome of an akoinit run.
+// All the methods are no-op on a nil report.
+type InitReport struct {
+	Command            string              `json:"command"`
+	StartTime          time.Time           `json:"startTime"`
+	Duration           string              `json:"duration"`
+	Phases             []PhaseReport       `json:"phases"`
+	NodeID             string              `json:"nodeID,omitempty"`
+	RackID             *int                `json:"rackID,omitempty"`
+	Endpoints          map[string][]string `json:"endpoints,omitempty"`
+	InitializedVolumes []string            `json:"initializedVolumes,omitempty"`
+	WipedVolumes       []string            `json:"wipedVolumes,omitempty"`
+	CleanedVolumes     []string            `json:"cleanedVolumes,omitempty"`
+	Error              string              `json:"error,omitempty"`
+	// Truncated is set when details were dropped to fit the termination message size limit.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// PhaseReport is the outcome of a single init phase.
+type PhaseReport struct {
+	Name     string `json:"name"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+// NewInitReport returns an empty report, started now.
+func NewInitReport() *InitReport {
+	return &InitReport{StartTime: time.Now()}
+}
+
+// startPhase records the start of a phase and returns the function recording its end.
+func (r *InitReport) startPhase(name string) func(err error) {
+	start := time.Now()
+
+	return func(err error) {
+		if r == nil {
+			return
+		}
+
+		phase := PhaseReport{
+			Name:     name,
+			Duration: time.Since(start).Round(time.Millisecond).String(),
+		}
+
+		if err != nil {
+			phase.Error = err.Error()
+		}
+
+		r.Phases = append(r.Phases, phase)
+	}
+}
+
+func (r *InitReport) setNode(nodeID string, rackID int) {
+	if r == nil {
+		return
+	}
+
+	r.NodeID = nodeID
+	r.RackID = &rackID
+}
+
+func (r *InitReport) setEndpoints(endpoints map[string][]string) {
+	if r == nil {
+		return
+	}
+
+	r.Endpoints = endpoints
+}
+
+func (r *InitReport) addInitializedVolumes(volumes ...string) {
+	if r == nil {
+		return
+	}
+
+	r.InitializedVolumes = append(r.InitializedVolumes, volumes...)
+}
+
+func (r *InitReport) addWipedVolumes(volumes ...string) {
+	if r == nil {
+		return
+	}
+
+	r.WipedVolumes = append(r.WipedVolumes, volumes...)
+}
+
+func (r *InitReport) addCleanedVolumes(volumes ...string) {
+	if r == nil {
+		return
+	}
+
+	r.CleanedVolumes = append(r.CleanedVolumes, volumes...)
+}
+
+// Finish records the command run and its final error.
+func (r *InitReport) Finish(command string, err error) {
+	if r == nil {
+		return
+	}
+
+	r.Command = command
+	r.Duration = time.Since(r.StartTime).Round(time.Millisecond).String()
+
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
+
+// Write writes the report to the termination log, if the file exists, and to the report file if set.
+// The termination log is only written when mounted by kubelet, it is not created.
+func (r *InitReport) Write(terminationLog, reportFile string) error {
+	if r == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	if reportFile != "" {
+		if err := os.WriteFile(reportFile, data, 0644); err != nil { //nolint:gocritic,gosec // file permission
+			return fmt.Errorf("failed to write init report %s: %v", reportFile, err)
+		}
+	}
+
+	if terminationLog == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(terminationLog, os.O_WRONLY|os.O_TRUNC, 0)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to write termination log %s: %v", terminationLog, err)
+	}
+
+	defer f.Close()
+
+	if _, err := f.Write(r.terminationMessage(data)); err != nil {
+		return fmt.Errorf("failed to write termination log %s: %v", terminationLog, err)
+	}
+
+	return nil
+}
+
+// terminationMessage fits the report in the termination message size limit,
+// dropping the volumes and endpoints first, then cutting the error.
+func (r *InitReport) terminationMessage(data []byte) []byte {
+	if len(data) <= terminationMessageMaxSize {
+		return data
+	}
+
+	short := *r
+	short.Endpoints = nil
+	short.InitializedVolumes = nil
+	short.WipedVolumes = nil
+	short.CleanedVolumes = nil
+	short.Truncated = true
+
+	for {
+		data, err := json.Marshal(&short)
+		if err != nil || len(data) <= terminationMessageMaxSize {
+			return data
+		}
+
+		switch {
+		case len(short.Phases) != 0:
+			short.Phases = nil
+		case len(short.Error) > 0:
+			short.Error = short.Error[:len(short.Error)/2]
+		default:
+			return data[:terminationMessageMaxSize]
+		}
+	}
+}
+
+// startPhase records the start of an init phase as an event and in the report,
+// and returns the function recording its outcome.
+func (initp *InitParams) startPhase(ctx goctx.Context, phase string) func(err error) {
+	initp.recorder.phaseStarted(ctx, phase)
+	endPhase := initp.report.startPhase(phase)
+
+	return func(err error) {
+		endPhase(err)
+		initp.recorder.phaseFinished(ctx, phase, err)
+	}
+}
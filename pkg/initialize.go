@@ -9,27 +9,29 @@ import (
 	asdbv1 "github.com/aerospike/aerospike-kubernetes-operator/v4/api/v1"
 )
 
-const (
-	configVolume = "/etc/aerospike"
-	configMapDir = "/etc/aerospike/configmap"
-)
-
 // ColdRestart initializes storage devices on first pod run.
-func (initp *InitParams) ColdRestart(ctx goctx.Context) error {
+func (initp *InitParams) ColdRestart(ctx goctx.Context) (err error) {
+	endPhase := initp.startPhase(ctx, "ColdRestart")
+	defer func() { endPhase(err) }()
+
 	// Create required directories.
 	if err := initp.makeWorkDir(); err != nil {
 		return err
 	}
 
+	configVolume := initp.paths.ConfigVolume
+	configMapDir := initp.paths.configMapDir()
+	configsDir := initp.paths.ConfigsDir
+
 	// Copy required files to config volume for initialization.
-	if err := initp.copyTemplates("/configs", configVolume); err != nil {
+	if err := initp.copyTemplates(configsDir, configVolume); err != nil {
 		return err
 	}
 
 	// Copy scripts and binaries needed for warm restart.
 	// Init should not fail if features.conf file is not present
 	// akoinit binary will always be present here, as same has been checked in entrypoint.sh
-	filesToCopy := [2]string{"/workdir/bin/akoinit", "/configs/features.conf"}
+	filesToCopy := [2]string{filepath.Join(initp.paths.BinDir, "akoinit"), filepath.Join(configsDir, "features.conf")}
 	for _, file := range filesToCopy {
 		if _, err := os.Stat(file); err == nil {
 			cmd := exec.Command("cp", "--dereference", file, configVolume)
@@ -39,26 +41,19 @@ func (initp *InitParams) ColdRestart(ctx goctx.Context) error {
 		}
 	}
 
-	if err := os.MkdirAll(configMapDir, 0755); err != nil { //nolint:gocritic // file permission
-		return err
-	}
-
-	matches, err := filepath.Glob("/configs/*")
+	files, err := readConfigDir(initp.logger, configsDir)
 	if err != nil {
 		return err
 	}
 
-	for _, path := range matches {
-		cmd := exec.Command("cp", "--dereference", "--recursive", path, configMapDir)
-		if err := cmd.Run(); err != nil {
-			return err
-		}
+	if err := writeAtomicDir(initp.logger, configMapDir, files); err != nil {
+		return err
 	}
 
 	initp.logger.Info("Copied all files from configmap to configmap directory",
-		"source", "/configs", "destination", configMapDir)
+		"source", configsDir, "destination", configMapDir)
 
-	if err := initp.createAerospikeConf(); err != nil {
+	if err := initp.createAerospikeConf(ctx); err != nil {
 		return err
 	}
 
@@ -0,0 +1,105 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	asdbv1 "github.com/aerospike/aerospike-kubernetes-operator/v4/api/v1"
+)
+
+// networkTypeLoadBalancer advertises the ingress of the LoadBalancer service created for the pod.
+// It is selected per access type with the network type annotations, see getAccessNetworkPolicy.
+const networkTypeLoadBalancer asdbv1.AerospikeNetworkType = "loadBalancer"
+
+const (
+	loadBalancerPollInterval    = 2 * time.Second
+	loadBalancerMaxPollInterval = 30 * time.Second
+)
+
+// usesLoadBalancer returns true if an access type of the network policy is loadBalancer.
+func (initp *InitParams) usesLoadBalancer() bool {
+	policy := initp.networkInfo.networkPolicy
+
+	for _, networkType := range []asdbv1.AerospikeNetworkType{
+		policy.AccessType, policy.AlternateAccessType, policy.TLSAccessType, policy.TLSAlternateAccessType,
+	} {
+		if networkType == networkTypeLoadBalancer {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getLoadBalancerIngress waits with backoff until the LoadBalancer service named after the pod is assigned an
+// ingress, and returns the ingress IPs and hostnames with the service and tls-service ports of the service.
+func (initp *InitParams) getLoadBalancerIngress(ctx context.Context) (addresses []string, servicePort,
+	serviceTLSPort int32, err error) {
+	pollCtx, cancel := context.WithTimeout(ctx, initp.network.LoadBalancerWaitTimeout)
+	defer cancel()
+
+	delay := loadBalancerPollInterval
+
+	// The service is read once without the wait timeout, so that a zero timeout still makes one attempt,
+	// e.g. when rendering offline.
+	getCtx := ctx
+
+	for {
+		service, getErr := getPodService(getCtx, initp.logger, initp.k8sClient, initp.namespace, initp.podName)
+
+		switch {
+		case getErr != nil:
+			err = getErr
+			initp.logger.Error(err, "Failed to get load balancer service, retrying", "service", initp.podName)
+		case service == nil:
+			err = fmt.Errorf("service %s not found", initp.podName)
+			initp.logger.Info("Waiting for load balancer service", "service", initp.podName)
+		default:
+			if addresses, servicePort, serviceTLSPort = loadBalancerEndpoint(service); len(addresses) != 0 {
+				initp.logger.Info("Found load balancer ingress", "service", initp.podName, "addresses", addresses,
+					"port", servicePort, "tls-port", serviceTLSPort)
+
+				return addresses, servicePort, serviceTLSPort, nil
+			}
+
+			err = fmt.Errorf("service %s has no load balancer ingress", initp.podName)
+			initp.logger.Info("Waiting for load balancer ingress", "service", initp.podName)
+		}
+
+		select {
+		case <-pollCtx.Done():
+			return nil, 0, 0, fmt.Errorf("load balancer service %s/%s got no ingress within %s: %v",
+				initp.namespace, initp.podName, initp.network.LoadBalancerWaitTimeout, err)
+		case <-time.After(wait.Jitter(delay, 0.1)):
+		}
+
+		getCtx = pollCtx
+		delay = min(2*delay, loadBalancerMaxPollInterval)
+	}
+}
+
+// loadBalancerEndpoint returns the ingress IPs and hostnames of the service with its service and tls-service ports.
+func loadBalancerEndpoint(service *corev1.Service) (addresses []string, servicePort, serviceTLSPort int32) {
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			addresses = append(addresses, ingress.IP)
+		} else if ingress.Hostname != "" {
+			addresses = append(addresses, ingress.Hostname)
+		}
+	}
+
+	for _, port := range service.Spec.Ports {
+		switch port.Name {
+		case "service":
+			servicePort = port.Port
+		case "tls-service":
+			serviceTLSPort = port.Port
+		}
+	}
+
+	return addresses, servicePort, serviceTLSPort
+}
@@ -0,0 +1,120 @@
+package pkg
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestGetLoadBalancerIngress(t *testing.T) {
+	scheme, err := newScheme()
+	if err != nil {
+		t.Fatalf("newScheme() error = %v", err)
+	}
+
+	ports := []corev1.ServicePort{{Name: "service", Port: 3000}, {Name: "tls-service", Port: 4333}}
+	ingress := []corev1.LoadBalancerIngress{{IP: "203.0.113.1"}, {Hostname: "aerospike.example.com"}}
+
+	tests := []struct {
+		name        string
+		waitTimeout time.Duration
+		// ingress is set on the service, after pendingGets gets without ingress. No service if nil.
+		ingress     []corev1.LoadBalancerIngress
+		pendingGets int
+		wantErr     string
+	}{
+		{
+			name:    "ingress without wait timeout",
+			ingress: ingress,
+		},
+		{
+			name:        "ingress after retry",
+			waitTimeout: 5 * time.Second,
+			ingress:     ingress,
+			pendingGets: 1,
+		},
+		{
+			name:    "no ingress without wait timeout",
+			ingress: []corev1.LoadBalancerIngress{},
+			wantErr: "has no load balancer ingress",
+		},
+		{
+			name:        "no service",
+			waitTimeout: 100 * time.Millisecond,
+			wantErr:     "service aerospike-0-0 not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+
+			if tt.ingress != nil {
+				builder = builder.WithObjects(&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: "aerospike-0-0", Namespace: "aerospike"},
+					Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, Ports: ports},
+					Status: corev1.ServiceStatus{
+						LoadBalancer: corev1.LoadBalancerStatus{Ingress: tt.ingress},
+					},
+				})
+			}
+
+			gets := 0
+
+			initp := &InitParams{
+				logger: logr.Discard(),
+				k8sClient: builder.WithInterceptorFuncs(interceptor.Funcs{
+					Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object,
+						opts ...client.GetOption) error {
+						gets++
+
+						if err := c.Get(ctx, key, obj, opts...); err != nil {
+							return err
+						}
+
+						if gets <= tt.pendingGets {
+							obj.(*corev1.Service).Status.LoadBalancer.Ingress = nil
+						}
+
+						return nil
+					},
+				}).Build(),
+				namespace: "aerospike",
+				podName:   "aerospike-0-0",
+				network:   NetworkOptions{LoadBalancerWaitTimeout: tt.waitTimeout},
+			}
+
+			addresses, servicePort, serviceTLSPort, err := initp.getLoadBalancerIngress(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("getLoadBalancerIngress() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("getLoadBalancerIngress() error = %v", err)
+			}
+
+			wantAddresses := []string{"203.0.113.1", "aerospike.example.com"}
+			if !reflect.DeepEqual(addresses, wantAddresses) || servicePort != 3000 || serviceTLSPort != 4333 {
+				t.Errorf("getLoadBalancerIngress() = %q, %d, %d, want %q, 3000, 4333", addresses, servicePort,
+					serviceTLSPort, wantAddresses)
+			}
+
+			if gets != tt.pendingGets+1 {
+				t.Errorf("getLoadBalancerIngress() got the service %d times, want %d", gets, tt.pendingGets+1)
+			}
+		})
+	}
+}
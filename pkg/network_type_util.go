@@ -0,0 +1,55 @@
+package pkg
+
+import (
+	"fmt"
+
+	asdbv1 "github.com/aerospike/aerospike-kubernetes-operator/v4/api/v1"
+)
+
+// networkTypeAnnotationSuffix is appended to the access type, e.g. aerospike.com/access-network-type, to form the
+// AerospikeCluster annotation selecting a network type the AerospikeCluster CRD does not accept for that access type.
+const networkTypeAnnotationSuffix = "-network-type"
+
+// annotatedNetworkTypes are the network types which can only be selected with the network type annotations.
+var annotatedNetworkTypes = []asdbv1.AerospikeNetworkType{networkTypeLoadBalancer, networkTypeDNSName}
+
+// getAccessNetworkPolicy returns the network policy of the AerospikeCluster with the network type of each access type
+// overridden by its AerospikeCluster annotation, if set.
+func (initp *InitParams) getAccessNetworkPolicy() (asdbv1.AerospikeNetworkPolicy, error) {
+	policy := initp.aeroCluster.Spec.AerospikeNetworkPolicy
+
+	networkTypes := map[string]*asdbv1.AerospikeNetworkType{
+		access:             &policy.AccessType,
+		alternateAccess:    &policy.AlternateAccessType,
+		tlsAccess:          &policy.TLSAccessType,
+		tlsAlternateAccess: &policy.TLSAlternateAccessType,
+	}
+
+	for addressType, networkType := range networkTypes {
+		annotation := "aerospike.com/" + addressType + networkTypeAnnotationSuffix
+
+		value, ok := initp.aeroCluster.Annotations[annotation]
+		if !ok {
+			continue
+		}
+
+		if !isAnnotatedNetworkType(asdbv1.AerospikeNetworkType(value)) {
+			return policy, fmt.Errorf("invalid network type %q in annotation %s, expected one of %v",
+				value, annotation, annotatedNetworkTypes)
+		}
+
+		*networkType = asdbv1.AerospikeNetworkType(value)
+	}
+
+	return policy, nil
+}
+
+func isAnnotatedNetworkType(networkType asdbv1.AerospikeNetworkType) bool {
+	for _, annotated := range annotatedNetworkTypes {
+		if networkType == annotated {
+			return true
+		}
+	}
+
+	return false
+}
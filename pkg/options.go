@@ -0,0 +1,131 @@
+package pkg
+
+import (
+	"path/filepath"
+	"time"
+)
+
+// Paths holds the filesystem locations used by the init container.
+// The defaults match the layout of the init image and the volumes mounted by the operator.
+// Relocating them allows running the complete init flow against a temp directory or a non-standard image layout.
+type Paths struct {
+	// ConfigVolume is the volume shared with the server container, holding aerospike.conf.
+	ConfigVolume string
+	// ConfigsDir is the mounted operator configmap.
+	ConfigsDir string
+	// BinDir holds the akoinit binary.
+	BinDir string
+	// FileSystemVolumesDir is the directory where filesystem volumes are mounted by name.
+	FileSystemVolumesDir string
+	// BlockVolumesDir is the directory where block volumes are mounted by name.
+	BlockVolumesDir string
+	// InitCmdline is the cmdline of the server container init process, checked before a warm restart.
+	InitCmdline string
+	// TerminationLog is the container termination message file the init report is written to, if it exists.
+	TerminationLog string
+}
+
+// RestartOptions holds the settings of the Aerospike server warm restart.
+type RestartOptions struct {
+	// ASDStartTimeout is the time allowed for the restarted server to come up and answer info requests.
+	ASDStartTimeout time.Duration
+	// DynamicConfig applies the config changes which allow it to the running server with set-config,
+	// and restarts the server only for static changes.
+	DynamicConfig bool
+}
+
+// ConfigMapOptions holds the settings of the operator configmap export on quick-restart and update-conf.
+type ConfigMapOptions struct {
+	// ExpectedConfHash is the aerospikeConfHash the configmap must hold before it is exported.
+	// If empty, the hash of the aerospike.conf template of the pod rack in the AerospikeCluster spec is expected.
+	ExpectedConfHash string
+	// WaitTimeout is the time allowed for the configmap to reach the expected hash.
+	WaitTimeout time.Duration
+}
+
+// NetworkOptions holds the settings of the network info discovery.
+type NetworkOptions struct {
+	// LoadBalancerWaitTimeout is the time allowed for the LoadBalancer service of the pod to get an ingress.
+	// The service is read once if zero.
+	LoadBalancerWaitTimeout time.Duration
+	// CustomNetworkWaitTimeout is the time allowed for the CNI network status of the pod to list IPs for every
+	// custom network in use. There is no wait if zero.
+	CustomNetworkWaitTimeout time.Duration
+	// ClusterDomain is the DNS domain of the k8s cluster, used by the default DNS name template.
+	ClusterDomain string
+}
+
+// Options holds the init container settings which are not derived from the k8s cluster.
+type Options struct {
+	Paths     Paths
+	Restart   RestartOptions
+	ConfigMap ConfigMapOptions
+	Network   NetworkOptions
+	// RedactKeys are aerospike.conf parameters masked in logs and the pod annotation, on top of the defaults.
+	RedactKeys []string
+	// ConfStorage is where the rendered aerospike.conf is published for the pod, one of the ConfStorage modes.
+	ConfStorage string
+	// ReportFile is an extra file the init report is written to, e.g. on a volume shared with the server container.
+	ReportFile string
+	// Report collects the outcome of the run, nil to not report.
+	Report *InitReport
+}
+
+// DefaultOptions returns the options used by the init image.
+func DefaultOptions() Options {
+	return Options{
+		Paths: Paths{
+			ConfigVolume:         "/etc/aerospike",
+			ConfigsDir:           "/configs",
+			BinDir:               "/workdir/bin",
+			FileSystemVolumesDir: "/workdir/filesystem-volumes",
+			BlockVolumesDir:      "/workdir/block-volumes",
+			InitCmdline:          "/proc/1/cmdline",
+			TerminationLog:       "/dev/termination-log",
+		},
+		Restart: RestartOptions{
+			ASDStartTimeout: 5 * time.Minute,
+		},
+		ConfigMap: ConfigMapOptions{
+			WaitTimeout: 2 * time.Minute,
+		},
+		Network: NetworkOptions{
+			LoadBalancerWaitTimeout:  5 * time.Minute,
+			CustomNetworkWaitTimeout: 2 * time.Minute,
+			ClusterDomain:            "cluster.local",
+		},
+		ConfStorage: ConfStorageAuto,
+	}
+}
+
+func (p *Paths) configMapDir() string {
+	return filepath.Join(p.ConfigVolume, "configmap")
+}
+
+func (p *Paths) aerospikeTemplateConf() string {
+	return filepath.Join(p.ConfigVolume, aerospikeTemplateConfFile)
+}
+
+func (p *Paths) aerospikeConf() string {
+	return filepath.Join(p.ConfigVolume, aerospikeConfFile)
+}
+
+func (p *Paths) confBackupDir() string {
+	return filepath.Join(p.ConfigVolume, "conf-backups")
+}
+
+func (p *Paths) secretsDir() string {
+	return filepath.Join(p.ConfigVolume, "secrets")
+}
+
+func (p *Paths) peers() string {
+	return filepath.Join(p.ConfigVolume, peersFile)
+}
+
+func (p *Paths) aerospikeOpensslCnf() string {
+	return filepath.Join(p.ConfigVolume, "openssl.cnf")
+}
+
+func (p *Paths) aerospikeFipsCnf() string {
+	return filepath.Join(p.ConfigVolume, "fips.cnf")
+}
@@ -0,0 +1,91 @@
+package pkg
+
+import (
+	goctx "context"
+	"encoding/json"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fieldManagerPrefix prefixes the server-side apply field managers of the pod annotations.
+// Every annotation group has its own field manager, so that applying one group never touches the others.
+const fieldManagerPrefix = "aerospike-init-"
+
+// confFieldManager owns the annotations publishing the rendered aerospike.conf.
+const confFieldManager = fieldManagerPrefix + "conf"
+
+// applyPodAnnotations sets the pod annotations owned by the field manager with a server-side apply.
+// Annotations earlier applied by the same field manager and missing from annotations are removed.
+// Only conflicts and transient errors are retried.
+func (initp *InitParams) applyPodAnnotations(ctx goctx.Context, fieldManager string,
+	annotations map[string]string) error {
+	podApply := corev1ac.Pod(initp.podName, initp.namespace).WithAnnotations(annotations)
+
+	// Force ownership of annotations written by earlier Update calls of the init container.
+	return retry.OnError(retry.DefaultBackoff, isTransientError, func() error {
+		return initp.k8sClient.Apply(ctx, podApply, client.FieldOwner(fieldManager), client.ForceOwnership)
+	})
+}
+
+// removePodAnnotations removes pod annotations with a merge patch, whichever field manager owns them.
+func (initp *InitParams) removePodAnnotations(ctx goctx.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	annotations := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		annotations[key] = nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	pod := &corev1.Pod{}
+	pod.Name = initp.podName
+	pod.Namespace = initp.namespace
+
+	return retry.OnError(retry.DefaultBackoff, isTransientError, func() error {
+		return initp.k8sClient.Patch(ctx, pod, client.RawPatch(types.MergePatchType, patch))
+	})
+}
+
+// patchPodAnnotation sets a single pod annotation to the JSON encoding of the given value.
+// A nil value removes the annotation. Each annotation key has its own field manager.
+func (initp *InitParams) patchPodAnnotation(ctx goctx.Context, key string, value interface{}) error {
+	if value == nil {
+		return initp.removePodAnnotations(ctx, key)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return initp.applyPodAnnotations(ctx, podAnnotationFieldManager(key), map[string]string{key: string(data)})
+}
+
+func podAnnotationFieldManager(key string) string {
+	return fieldManagerPrefix + strings.ReplaceAll(strings.TrimPrefix(key, "aerospike.com/"), "/", "-")
+}
+
+// isTransientError returns true for the errors worth retrying a k8s API call on.
+// Permission, validation and not found errors are not retried.
+func isTransientError(err error) bool {
+	return apierrors.IsConflict(err) || apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) ||
+		apierrors.IsTooManyRequests(err) || apierrors.IsServiceUnavailable(err) || apierrors.IsInternalError(err) ||
+		utilnet.IsConnectionReset(err) || utilnet.IsProbableEOF(err)
+}
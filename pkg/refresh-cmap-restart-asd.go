@@ -3,10 +3,17 @@ package pkg
 import (
 	goctx "context"
 	"fmt"
+	"os"
+	"path/filepath"
+
+	asdbv1 "github.com/aerospike/aerospike-kubernetes-operator/v4/api/v1"
 )
 
 // QuickRestart refreshes Aerospike config map and tries to warm restart Aerospike.
-func (initp *InitParams) QuickRestart(ctx goctx.Context, cmName, cmNamespace string) error {
+func (initp *InitParams) QuickRestart(ctx goctx.Context, cmName, cmNamespace string) (err error) {
+	endPhase := initp.startPhase(ctx, "QuickRestart")
+	defer func() { endPhase(err) }()
+
 	if cmNamespace == "" {
 		return fmt.Errorf("kubernetes namespace required as an argument")
 	}
@@ -15,19 +22,92 @@ func (initp *InitParams) QuickRestart(ctx goctx.Context, cmName, cmNamespace str
 		return fmt.Errorf("aerospike configmap required as an argument")
 	}
 
-	if err := initp.ExportK8sConfigmap(ctx, cmNamespace, cmName, configMapDir); err != nil {
+	if err := initp.ExportK8sConfigmap(ctx, cmNamespace, cmName, initp.paths.configMapDir()); err != nil {
 		return err
 	}
 
-	if err := initp.restartASD(); err != nil {
+	// After a failed dynamic config update the server runs with part of aerospike.conf, restart it.
+	dynamicConfigFailed := initp.aeroCluster.Status.Pods[initp.podName].DynamicConfigUpdateStatus != asdbv1.Empty
+
+	unchanged, err := initp.isRenderedConfUnchanged(ctx)
+	if err != nil {
 		return err
 	}
 
+	if unchanged && !dynamicConfigFailed {
+		initp.logger.Info("Rendered aerospike conf is unchanged, skipping Aerospike server restart")
+		initp.recorder.normal(ctx, reasonRestartSkipped, "Rendered aerospike.conf is unchanged, restart skipped")
+
+		// Update pod status in the k8s aerospike cluster object
+		return initp.manageVolumesAndUpdateStatus(ctx, "noRestart")
+	}
+
+	if initp.restart.DynamicConfig && !dynamicConfigFailed {
+		if err := initp.applyConfDynamically(ctx); err != nil {
+			initp.recorder.warning(ctx, reasonWarmRestartFailed, "Failed to apply the refreshed config: %v", err)
+			return err
+		}
+	} else {
+		if err := initp.restartASD(ctx); err != nil {
+			initp.recorder.warning(ctx, reasonWarmRestartFailed, "Aerospike server warm restart failed: %v", err)
+			return err
+		}
+
+		initp.recorder.normal(ctx, reasonWarmRestart, "Aerospike server warm restarted with the refreshed config")
+	}
+
 	// Update pod status in the k8s aerospike cluster object
 	return initp.manageVolumesAndUpdateStatus(ctx, "quickRestart")
 }
 
-func (initp *InitParams) UpdateConf(ctx goctx.Context, cmName, cmNamespace string) error {
+// isRenderedConfUnchanged renders aerospike.conf in memory from the exported configmap
+// and returns true if it is byte-identical to the aerospike.conf the server is running with
+// and the secret files it references hold the current secret values. Nothing is written.
+func (initp *InitParams) isRenderedConfUnchanged(ctx goctx.Context) (bool, error) {
+	current, err := os.ReadFile(initp.paths.aerospikeConf())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	template, err := os.ReadFile(filepath.Join(initp.paths.configMapDir(), aerospikeTemplateConfFile))
+	if err != nil {
+		return false, err
+	}
+
+	peersData, err := os.ReadFile(filepath.Join(initp.paths.configMapDir(), peersFile))
+	if err != nil {
+		return false, err
+	}
+
+	rendered, err := initp.renderAerospikeConf(template, peersData)
+	if err != nil {
+		return false, err
+	}
+
+	// Secret files are written by the restart only, a changed secret value is a config change too.
+	resolved, changedSecretFiles, err := initp.resolveSecretPlaceholdersDryRun(ctx, rendered)
+	if err != nil {
+		return false, err
+	}
+
+	initp.renderedConf = rendered
+
+	if len(changedSecretFiles) != 0 {
+		initp.logger.Info("Secret files referenced by aerospike conf changed", "files", changedSecretFiles)
+		return false, nil
+	}
+
+	return resolved == string(current), nil
+}
+
+func (initp *InitParams) UpdateConf(ctx goctx.Context, cmName, cmNamespace string) (err error) {
+	endPhase := initp.startPhase(ctx, "UpdateConf")
+	defer func() { endPhase(err) }()
+
 	if cmNamespace == "" {
 		return fmt.Errorf("kubernetes namespace required as an argument")
 	}
@@ -36,16 +116,16 @@ func (initp *InitParams) UpdateConf(ctx goctx.Context, cmName, cmNamespace strin
 		return fmt.Errorf("aerospike configmap required as an argument")
 	}
 
-	if err := initp.ExportK8sConfigmap(ctx, cmNamespace, cmName, configMapDir); err != nil {
+	if err := initp.ExportK8sConfigmap(ctx, cmNamespace, cmName, initp.paths.configMapDir()); err != nil {
 		return err
 	}
 
 	// Create new Aerospike configuration
-	if err := initp.copyTemplates(configMapDir, configVolume); err != nil {
+	if err := initp.copyTemplates(initp.paths.configMapDir(), initp.paths.ConfigVolume); err != nil {
 		return err
 	}
 
-	if err := initp.createAerospikeConf(); err != nil {
+	if err := initp.createAerospikeConf(ctx); err != nil {
 		return err
 	}
 
@@ -0,0 +1,131 @@
+package pkg
+
+import (
+	goctx "context"
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	asdbv1 "github.com/aerospike/aerospike-kubernetes-operator/v4/api/v1"
+)
+
+// OfflineObjects holds the paths of the local YAML or JSON manifests used to render aerospike.conf
+// without a live API server. ClusterFile and PodFile are required, NodeFile and ServiceFile are only
+// needed for the host and per-pod service based network types.
+type OfflineObjects struct {
+	ClusterFile string
+	PodFile     string
+	NodeFile    string
+	ServiceFile string
+}
+
+// PopulateOfflineInitParams gathers the required info from local manifests instead of the k8s cluster.
+// The objects are served through an in-memory client so that the same code path as the init container is used.
+func PopulateOfflineInitParams(ctx goctx.Context, opts *Options, objects *OfflineObjects) (*InitParams, error) {
+	logger := newLogger()
+
+	scheme, err := newScheme()
+	if err != nil {
+		return nil, err
+	}
+
+	if objects.ClusterFile == "" {
+		return nil, fmt.Errorf("AerospikeCluster manifest required as an argument")
+	}
+
+	if objects.PodFile == "" {
+		return nil, fmt.Errorf("pod manifest required as an argument")
+	}
+
+	aeroCluster := &asdbv1.AerospikeCluster{}
+	if err := decodeObjectFile(scheme, objects.ClusterFile, aeroCluster); err != nil {
+		return nil, err
+	}
+
+	pod := &corev1.Pod{}
+	if err := decodeObjectFile(scheme, objects.PodFile, pod); err != nil {
+		return nil, err
+	}
+
+	if pod.Namespace == "" {
+		pod.Namespace = aeroCluster.Namespace
+	}
+
+	clientObjects := []client.Object{aeroCluster, pod}
+
+	if objects.NodeFile != "" {
+		node := &corev1.Node{}
+		if err := decodeObjectFile(scheme, objects.NodeFile, node); err != nil {
+			return nil, err
+		}
+
+		clientObjects = append(clientObjects, node)
+	}
+
+	if objects.ServiceFile != "" {
+		service := &corev1.Service{}
+		if err := decodeObjectFile(scheme, objects.ServiceFile, service); err != nil {
+			return nil, err
+		}
+
+		if service.Namespace == "" {
+			service.Namespace = pod.Namespace
+		}
+
+		clientObjects = append(clientObjects, service)
+	}
+
+	logger.Info("Gathering all the required info from local manifests",
+		"cluster", objects.ClusterFile, "pod", objects.PodFile, "node", objects.NodeFile,
+		"service", objects.ServiceFile)
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(clientObjects...).Build()
+
+	// Nothing updates the local manifests, the load balancer ingress and custom network IPs are read once.
+	offlineOpts := *opts
+	offlineOpts.Network.LoadBalancerWaitTimeout = 0
+	offlineOpts.Network.CustomNetworkWaitTimeout = 0
+
+	return newInitParams(ctx, logger, k8sClient, &offlineOpts, &podEnv{
+		podName:     pod.Name,
+		namespace:   pod.Namespace,
+		clusterName: aeroCluster.Name,
+		hostIP:      pod.Status.HostIP,
+		podIP:       pod.Status.PodIP,
+	})
+}
+
+// decodeObjectFile reads a YAML or JSON manifest and decodes it into the given object.
+func decodeObjectFile(scheme *runtime.Scheme, path string, into runtime.Object) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if _, _, err := serializer.NewCodecFactory(scheme).UniversalDeserializer().Decode(data, nil, into); err != nil {
+		return fmt.Errorf("failed to decode %s: %v", path, err)
+	}
+
+	return nil
+}
+
+// RenderAerospikeConf renders the given template and peers list into the final aerospike.conf content.
+// Nothing is written to the filesystem.
+func (initp *InitParams) RenderAerospikeConf(template, peersData []byte) (string, error) {
+	return initp.renderAerospikeConf(template, peersData)
+}
+
+// Endpoints returns the access endpoints computed by the last render, keyed by address type.
+func (initp *InitParams) Endpoints() map[string][]string {
+	return map[string][]string{
+		access:             initp.getEndpoints(access),
+		alternateAccess:    initp.getEndpoints(alternateAccess),
+		tlsAccess:          initp.getEndpoints(tlsAccess),
+		tlsAlternateAccess: initp.getEndpoints(tlsAlternateAccess),
+	}
+}
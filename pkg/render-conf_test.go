@@ -0,0 +1,70 @@
+package pkg
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPopulateOfflineInitParamsErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	writeManifest := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		return path
+	}
+
+	cluster := writeManifest("cluster.yaml", "apiVersion: asdb.aerospike.com/v1\nkind: AerospikeCluster\n"+
+		"metadata:\n  name: aerospike\n  namespace: aerospike\n")
+	pod := writeManifest("pod.yaml", "apiVersion: v1\nkind: Pod\nmetadata:\n  name: aerospike-0-0\n")
+	invalid := writeManifest("invalid.yaml", "kind: [")
+
+	tests := []struct {
+		name    string
+		objects OfflineObjects
+		wantErr string
+	}{
+		{
+			name:    "no cluster manifest",
+			objects: OfflineObjects{PodFile: pod},
+			wantErr: "AerospikeCluster manifest required",
+		},
+		{
+			name:    "no pod manifest",
+			objects: OfflineObjects{ClusterFile: cluster},
+			wantErr: "pod manifest required",
+		},
+		{
+			name:    "missing manifest",
+			objects: OfflineObjects{ClusterFile: filepath.Join(dir, "missing.yaml"), PodFile: pod},
+			wantErr: "no such file",
+		},
+		{
+			name:    "invalid pod manifest",
+			objects: OfflineObjects{ClusterFile: cluster, PodFile: invalid},
+			wantErr: "failed to decode " + invalid,
+		},
+		{
+			name:    "invalid node manifest",
+			objects: OfflineObjects{ClusterFile: cluster, PodFile: pod, NodeFile: invalid},
+			wantErr: "failed to decode " + invalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &Options{ConfStorage: ConfStorageAuto}
+
+			_, err := PopulateOfflineInitParams(context.Background(), opts, &tt.objects)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("PopulateOfflineInitParams() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
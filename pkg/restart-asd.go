@@ -1,8 +1,12 @@
 package pkg
 
 import (
+	goctx "context"
+	"errors"
 	"fmt"
+	"net"
 	"os"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -10,27 +14,60 @@ import (
 	"github.com/mitchellh/go-ps"
 )
 
-func (initp *InitParams) restartASD() error {
-	data, err := os.ReadFile("/proc/1/cmdline")
+func (initp *InitParams) restartASD(ctx goctx.Context) error {
+	if err := initp.checkWarmRestartSupported(); err != nil {
+		return err
+	}
+
+	// Keep the config the server is running with to roll back to if the new one does not come up.
+	lastGoodBackup, err := initp.lastGoodConfBackup()
+	if err != nil {
+		return fmt.Errorf("failed to backup aerospike conf: %v", err)
+	}
+
+	// Create new Aerospike configuration
+	if err := initp.copyTemplates(initp.paths.configMapDir(), initp.paths.ConfigVolume); err != nil {
+		return err
+	}
+
+	if err := initp.createAerospikeConf(ctx); err != nil {
+		return err
+	}
+
+	return initp.restartASDWithRollback(ctx, lastGoodBackup)
+}
+
+// checkWarmRestartSupported checks that the server runs under tini to be able to warm restart.
+func (initp *InitParams) checkWarmRestartSupported() error {
+	data, err := os.ReadFile(initp.paths.InitCmdline)
 	if err != nil {
 		return err
 	}
 
 	strData := string(data)
-	// Check if we are running under tini to be able to warm restart.
 	if !strings.Contains(strData, "tini") || !strings.Contains(strData, "-r") {
 		return fmt.Errorf("warm restart not supported - aborting")
 	}
 
-	// Create new Aerospike configuration
-	if err := initp.copyTemplates(configMapDir, configVolume); err != nil {
-		return err
-	}
+	return nil
+}
+
+// restartASDWithRollback restarts the Aerospike server with the new aerospike.conf,
+// and restores the given backup if the server does not come back healthy.
+func (initp *InitParams) restartASDWithRollback(ctx goctx.Context, lastGoodBackup string) error {
+	if err := initp.signalASDRestart(ctx); err != nil {
+		if errors.Is(err, errASDStartFailed) {
+			return initp.rollbackConf(ctx, lastGoodBackup, err)
+		}
 
-	if err := initp.createAerospikeConf(); err != nil {
 		return err
 	}
 
+	return nil
+}
+
+// signalASDRestart restarts the Aerospike server through the init process and waits for it to be healthy.
+func (initp *InitParams) signalASDRestart(ctx goctx.Context) error {
 	processes, psErr := ps.Processes()
 	if psErr != nil {
 		return psErr
@@ -41,7 +78,7 @@ func (initp *InitParams) restartASD() error {
 
 	// Get current asd pid
 	for _, proc := range processes {
-		if proc.Executable() == "asd" {
+		if proc.Executable() == asdExecutable {
 			asdPid = proc.Pid()
 		}
 	}
@@ -78,5 +115,100 @@ func (initp *InitParams) restartASD() error {
 
 	initp.logger.Info("Successfully terminated Aerospike server during warm restart")
 
-	return nil
+	return initp.waitForASDStart(ctx, asdPid)
+}
+
+// waitForASDStart waits for a new asd process to replace the old one and answer info requests.
+func (initp *InitParams) waitForASDStart(ctx goctx.Context, oldPid int) error {
+	timeout := initp.restart.ASDStartTimeout
+
+	ctx, cancel := goctx.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(asdStartPollInterval)
+	defer ticker.Stop()
+
+	newPid := -1
+
+	for newPid == -1 {
+		processes, err := ps.Processes()
+		if err != nil {
+			return err
+		}
+
+		for _, proc := range processes {
+			if proc.Executable() == asdExecutable && proc.Pid() != oldPid {
+				newPid = proc.Pid()
+			}
+		}
+
+		if newPid != -1 {
+			break
+		}
+
+		initp.logger.Info("Waiting for Aerospike server to start ...")
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("aborting warm start - %w within %s", errASDStartFailed, timeout)
+		case <-ticker.C:
+		}
+	}
+
+	initp.logger.Info("Aerospike server started", "pid", newPid)
+
+	address := initp.infoAddress()
+	if address == "" {
+		initp.logger.Info("No plain service or admin port configured, skipping Aerospike server health check")
+		return nil
+	}
+
+	// Not an Aerospike server failure, the conf is not rolled back.
+	policy, err := initp.infoClientPolicy(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get info client policy: %v", err)
+	}
+
+	var lastErr error
+
+	for {
+		info, err := requestInfo(ctx, policy, address, infoCommandStatus, infoCommandNode)
+		if err == nil && info[infoCommandStatus] == "ok" && info[infoCommandNode] != "" {
+			initp.logger.Info("Aerospike server is healthy after warm restart", "pid", newPid,
+				"node", info[infoCommandNode])
+
+			return nil
+		}
+
+		lastErr = err
+		if err == nil {
+			lastErr = fmt.Errorf("unexpected info response status=%q node=%q", info[infoCommandStatus],
+				info[infoCommandNode])
+		}
+
+		initp.logger.Info("Waiting for Aerospike server to become healthy ...", "address", address,
+			"error", lastErr.Error())
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("aborting warm start - %w, not healthy within %s: %v",
+				errASDStartFailed, timeout, lastErr)
+		case <-ticker.C:
+		}
+	}
+}
+
+// infoAddress returns the local address to send info requests to, the plain service port
+// and else the plain admin port. Empty if the server only listens on TLS ports.
+func (initp *InitParams) infoAddress() string {
+	port := initp.networkInfo.servicePort
+	if port == 0 {
+		port = initp.networkInfo.adminPort
+	}
+
+	if port == 0 {
+		return ""
+	}
+
+	return net.JoinHostPort("127.0.0.1", strconv.Itoa(int(port)))
 }
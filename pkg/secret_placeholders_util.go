@@ -0,0 +1,145 @@
+package pkg
+
+import (
+	"bytes"
+	goctx "context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/aerospike/aerospike-kubernetes-operator/v4/pkg/utils"
+)
+
+const (
+	secretPlaceholder     = "secret"
+	secretFilePlaceholder = "secretfile"
+)
+
+// secretPlaceholderRegex matches ${secret:<name>/<key>} and ${secretfile:<name>/<key>} placeholders.
+// Secret names are DNS subdomains and keys are made of alphanumerics, '-', '_' and '.'.
+var secretPlaceholderRegex = regexp.MustCompile(
+	`\$\{(` + secretPlaceholder + `|` + secretFilePlaceholder + `):([a-z0-9][a-z0-9.-]*)/([-._a-zA-Z0-9]+)\}`)
+
+// resolveSecretPlaceholders replaces the secret placeholders of the rendered aerospike.conf with values
+// from the k8s Secrets in the pod namespace. ${secret:name/key} is replaced by the secret value, and
+// ${secretfile:name/key} by the path of a file holding the secret value, readable by the owner only.
+func (initp *InitParams) resolveSecretPlaceholders(ctx goctx.Context, conf string) (string, error) {
+	return initp.resolvePlaceholders(ctx, conf, initp.writeSecretFile)
+}
+
+// resolveSecretPlaceholdersDryRun resolves the secret placeholders like resolveSecretPlaceholders without writing
+// the secret files. It also returns the paths of the secret files which are missing or hold a different value.
+func (initp *InitParams) resolveSecretPlaceholdersDryRun(ctx goctx.Context, conf string) (resolved string,
+	changedFiles []string, err error) {
+	resolved, err = initp.resolvePlaceholders(ctx, conf, func(name, key string, value []byte) (string, error) {
+		path := initp.secretFilePath(name, key)
+
+		current, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+
+		if err != nil || !bytes.Equal(current, value) {
+			changedFiles = append(changedFiles, path)
+		}
+
+		return path, nil
+	})
+
+	return resolved, changedFiles, err
+}
+
+// resolvePlaceholders replaces the secret placeholders, the ${secretfile:name/key} ones with the path
+// returned by secretFile for the secret value.
+func (initp *InitParams) resolvePlaceholders(ctx goctx.Context, conf string,
+	secretFile func(name, key string, value []byte) (string, error)) (string, error) {
+	secrets := make(map[string]*corev1.Secret)
+
+	var resolveErr error
+
+	resolved := secretPlaceholderRegex.ReplaceAllStringFunc(conf, func(placeholder string) string {
+		if resolveErr != nil {
+			return placeholder
+		}
+
+		match := secretPlaceholderRegex.FindStringSubmatch(placeholder)
+		kind, name, key := match[1], match[2], match[3]
+
+		secret, ok := secrets[name]
+		if !ok {
+			secret = &corev1.Secret{}
+			if err := initp.k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: initp.namespace},
+				secret); err != nil {
+				resolveErr = fmt.Errorf("failed to get secret %s for placeholder %s: %v", name, placeholder, err)
+				return placeholder
+			}
+
+			secrets[name] = secret
+		}
+
+		value, ok := secret.Data[key]
+		if !ok {
+			resolveErr = fmt.Errorf("key %s not found in secret %s for placeholder %s", key, name, placeholder)
+			return placeholder
+		}
+
+		if kind == secretPlaceholder {
+			if len(value) != 0 && !utils.ContainsString(initp.secretValues, string(value)) {
+				initp.secretValues = append(initp.secretValues, string(value))
+			}
+
+			return string(value)
+		}
+
+		path, err := secretFile(name, key, value)
+		if err != nil {
+			resolveErr = fmt.Errorf("failed to resolve secret file for placeholder %s: %v", placeholder, err)
+			return placeholder
+		}
+
+		return path
+	})
+
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+
+	return resolved, nil
+}
+
+// secretFilePath returns the path of the file holding the given secret value, <config-volume>/secrets/<name>/<key>.
+func (initp *InitParams) secretFilePath(name, key string) string {
+	return filepath.Join(initp.paths.secretsDir(), name, key)
+}
+
+// writeSecretFile writes the secret value to its secret file with mode 0600.
+func (initp *InitParams) writeSecretFile(name, key string, value []byte) (string, error) {
+	path := initp.secretFilePath(name, key)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+
+	tmpFile := path + ".tmp"
+
+	if err := os.WriteFile(tmpFile, value, 0600); err != nil {
+		return "", err
+	}
+
+	// WriteFile keeps the mode of an existing file.
+	if err := os.Chmod(tmpFile, 0600); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmpFile, path); err != nil {
+		return "", err
+	}
+
+	initp.logger.Info("Wrote secret file", "secret", name, "key", key, "path", path)
+
+	return path, nil
+}
@@ -0,0 +1,210 @@
+package pkg
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newSecretPlaceholderInitParams(t *testing.T) *InitParams {
+	t.Helper()
+
+	scheme, err := newScheme()
+	if err != nil {
+		t.Fatalf("newScheme() error = %v", err)
+	}
+
+	secrets := []*corev1.Secret{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "aerospike"},
+			Data:       map[string][]byte{"key-password": []byte("s3cret"), "ca.pem": []byte("ca")},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "other"},
+			Data:       map[string][]byte{"key-password": []byte("other")},
+		},
+	}
+
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, secret := range secrets {
+		builder = builder.WithObjects(secret)
+	}
+
+	return &InitParams{
+		logger:    logr.Discard(),
+		k8sClient: builder.Build(),
+		namespace: "aerospike",
+		paths:     Paths{ConfigVolume: t.TempDir()},
+	}
+}
+
+func TestResolveSecretPlaceholders(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    string
+		want    string
+		wantErr string
+		// wantFiles are the secret files expected with their content, relative to the secrets directory.
+		wantFiles   map[string]string
+		wantSecrets []string
+	}{
+		{
+			name: "no placeholders",
+			conf: "service {\n\tcluster-name test\n}\n",
+			want: "service {\n\tcluster-name test\n}\n",
+		},
+		{
+			name:        "secret value",
+			conf:        "key-file-password ${secret:tls/key-password}\nfoo ${secret:tls/key-password}\n",
+			want:        "key-file-password s3cret\nfoo s3cret\n",
+			wantSecrets: []string{"s3cret"},
+		},
+		{
+			name:      "secret file",
+			conf:      "ca-file ${secretfile:tls/ca.pem}\n",
+			want:      "ca-file {secrets}/tls/ca.pem\n",
+			wantFiles: map[string]string{"tls/ca.pem": "ca"},
+		},
+		{
+			name:    "missing secret",
+			conf:    "password ${secret:missing/key}\n",
+			wantErr: "failed to get secret missing",
+		},
+		{
+			name:    "missing key",
+			conf:    "password ${secret:tls/missing}\n",
+			wantErr: "key missing not found in secret tls",
+		},
+		{
+			name: "not a placeholder",
+			conf: "password ${secret:TLS/key}\n",
+			want: "password ${secret:TLS/key}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initp := newSecretPlaceholderInitParams(t)
+			secretsDir := initp.paths.secretsDir()
+
+			got, err := initp.resolveSecretPlaceholders(context.Background(), tt.conf)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveSecretPlaceholders() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("resolveSecretPlaceholders() error = %v", err)
+			}
+
+			if want := strings.ReplaceAll(tt.want, "{secrets}", secretsDir); got != want {
+				t.Errorf("resolveSecretPlaceholders() = %q, want %q", got, want)
+			}
+
+			for name, content := range tt.wantFiles {
+				path := filepath.Join(secretsDir, name)
+
+				data, err := os.ReadFile(path)
+				if err != nil || string(data) != content {
+					t.Errorf("secret file %s = %q, %v, want %q", name, data, err, content)
+				}
+
+				if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+					t.Errorf("secret file %s mode = %v, %v, want 0600", name, info.Mode().Perm(), err)
+				}
+			}
+
+			if !reflect.DeepEqual(initp.secretValues, tt.wantSecrets) {
+				t.Errorf("secretValues = %q, want %q", initp.secretValues, tt.wantSecrets)
+			}
+		})
+	}
+}
+
+func TestResolveSecretPlaceholdersDryRun(t *testing.T) {
+	const conf = "ca-file ${secretfile:tls/ca.pem}\npassword-file ${secretfile:tls/key-password}\n"
+
+	tests := []struct {
+		name string
+		// existing are the secret files already written, relative to the secrets directory.
+		existing    map[string]string
+		wantChanged []string
+	}{
+		{
+			name:        "no secret files",
+			wantChanged: []string{"tls/ca.pem", "tls/key-password"},
+		},
+		{
+			name:        "stale secret file",
+			existing:    map[string]string{"tls/ca.pem": "ca", "tls/key-password": "old"},
+			wantChanged: []string{"tls/key-password"},
+		},
+		{
+			name:     "up to date",
+			existing: map[string]string{"tls/ca.pem": "ca", "tls/key-password": "s3cret"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initp := newSecretPlaceholderInitParams(t)
+			secretsDir := initp.paths.secretsDir()
+
+			for name, content := range tt.existing {
+				path := filepath.Join(secretsDir, name)
+
+				if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, changed, err := initp.resolveSecretPlaceholdersDryRun(context.Background(), conf)
+			if err != nil {
+				t.Fatalf("resolveSecretPlaceholdersDryRun() error = %v", err)
+			}
+
+			want := "ca-file " + filepath.Join(secretsDir, "tls/ca.pem") + "\npassword-file " +
+				filepath.Join(secretsDir, "tls/key-password") + "\n"
+			if got != want {
+				t.Errorf("resolveSecretPlaceholdersDryRun() = %q, want %q", got, want)
+			}
+
+			var wantChanged []string
+			for _, name := range tt.wantChanged {
+				wantChanged = append(wantChanged, filepath.Join(secretsDir, name))
+			}
+
+			if !reflect.DeepEqual(changed, wantChanged) {
+				t.Errorf("resolveSecretPlaceholdersDryRun() changed files = %q, want %q", changed, wantChanged)
+			}
+
+			// Nothing is written by a dry run.
+			for name, content := range tt.existing {
+				if data, err := os.ReadFile(filepath.Join(secretsDir, name)); err != nil || string(data) != content {
+					t.Errorf("secret file %s = %q, %v, want %q", name, data, err, content)
+				}
+			}
+
+			if len(tt.existing) == 0 {
+				if _, err := os.Stat(secretsDir); !os.IsNotExist(err) {
+					t.Errorf("dry run created the secrets directory, stat error = %v", err)
+				}
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@ package pkg
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"net"
 	"os"
@@ -10,7 +9,6 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
-	"sync"
 
 	"github.com/go-logr/logr"
 	jp "gomodules.xyz/jsonpatch/v2"
@@ -18,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -27,13 +26,13 @@ import (
 )
 
 const (
-	fileSystemMountPoint = "/workdir/filesystem-volumes"
-	blockMountPoint      = "/workdir/block-volumes"
-	baseWipeVersion      = 6
+	baseWipeVersion = 6
 )
 
 type Volume struct {
 	podName             string
+	pvcUID              string
+	mountRoot           string
 	volumeMode          string
 	volumeName          string
 	effectiveWipeMethod string
@@ -42,11 +41,7 @@ type Volume struct {
 }
 
 func (v *Volume) getMountPoint() string {
-	if v.volumeMode == string(corev1.PersistentVolumeBlock) {
-		return filepath.Join(blockMountPoint, v.volumeName)
-	}
-
-	return filepath.Join(fileSystemMountPoint, v.volumeName)
+	return filepath.Join(v.mountRoot, v.volumeName)
 }
 
 func getImageVersion(image string) (majorVersion int, err error) {
@@ -65,22 +60,25 @@ func getImageVersion(image string) (majorVersion int, err error) {
 	return majorVersion, err
 }
 
-func execute(cmd []string, stderr *os.File) error {
+func execute(ctx context.Context, cmd []string) error {
 	if len(cmd) == 0 {
 		return nil
 	}
 
-	command := exec.Command(cmd[0], cmd[1:]...) //nolint:gosec // internal command array
+	command := exec.CommandContext(ctx, cmd[0], cmd[1:]...) //nolint:gosec // internal command array
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
 
-	if stderr != nil {
-		command.Stdout = stderr
-		command.Stderr = stderr
-	} else {
-		command.Stdout = os.Stdout
-		command.Stderr = os.Stderr
+	if err := command.Run(); err != nil {
+		// A command killed on cancellation fails with its exit status, keep the cause.
+		if ctx.Err() != nil {
+			return fmt.Errorf("%v: %w", err, ctx.Err())
+		}
+
+		return err
 	}
 
-	return command.Run()
+	return nil
 }
 
 func (initp *InitParams) getPodImages(pod *corev1.Pod) (serverImage, initImage string) {
@@ -134,7 +132,7 @@ func (initp *InitParams) getNodeMetadata() *asdbv1.AerospikePodStatus {
 		PodPort:        int(servicePort),
 		PodAdminPort:   adminPort,
 		Aerospike: asdbv1.AerospikeInstanceSummary{
-			ClusterName: clusterName,
+			ClusterName: initp.aeroCluster.Name,
 			NodeID:      initp.nodeID,
 			TLSName:     initp.networkInfo.serviceTLSName,
 		},
@@ -184,11 +182,16 @@ func getPersistentVolumes(volumes []asdbv1.VolumeSpec) (volumeList []asdbv1.Volu
 	return volumeList
 }
 
-func newVolume(podName string, vol *asdbv1.VolumeSpec) *Volume {
+func (initp *InitParams) newVolume(vol *asdbv1.VolumeSpec) *Volume {
 	var volume Volume
 
-	volume.podName = podName
+	volume.podName = initp.podName
 	volume.volumeMode = string(vol.Source.PersistentVolume.VolumeMode)
+	volume.mountRoot = initp.paths.FileSystemVolumesDir
+
+	if volume.volumeMode == string(corev1.PersistentVolumeBlock) {
+		volume.mountRoot = initp.paths.BlockVolumesDir
+	}
 	volume.volumeName = vol.Name
 	volume.effectiveWipeMethod = string(vol.WipeMethod)
 	volume.effectiveInitMethod = string(vol.InitMethod)
@@ -200,49 +203,26 @@ func newVolume(podName string, vol *asdbv1.VolumeSpec) *Volume {
 	return &volume
 }
 
-func runDD(logger logr.Logger, cmd []string, wg *sync.WaitGroup, guard chan struct{}) {
-	stderr, err := os.CreateTemp("/tmp", "init-stderr")
-
-	defer func() {
-		stderr.Close()
-		// #nosec G703 -- path from os.CreateTemp is safe
-		os.Remove(stderr.Name())
-		<-guard
-		wg.Done()
-	}()
-
-	if err != nil {
-		panic(err.Error())
+func runZero(ctx context.Context, logger logr.Logger, zeroer *deviceZeroer) error {
+	if err := zeroer.run(ctx); err != nil {
+		return err
 	}
 
-	if err := execute(cmd, stderr); err != nil {
-		// #nosec G703 -- path from os.CreateTemp is safe
-		dat, err := os.ReadFile(stderr.Name())
-		if err != nil {
-			panic(err.Error())
-		}
+	logger.Info("Zeroing completed", "device", zeroer.device, "bytes-written", zeroer.bytesWritten())
 
-		if !strings.Contains(string(dat), "No space left on device") {
-			panic(errors.New(string(dat)))
-		}
-	}
-
-	logger.Info("Execution completed", "cmd", cmd)
+	return nil
 }
 
-func runBlkdiscard(logger logr.Logger, cmdList [][]string, wg *sync.WaitGroup, guard chan struct{}) {
-	defer func() {
-		<-guard // Ensure the guard channel is released
-		wg.Done()
-	}()
-
+func runBlkdiscard(ctx context.Context, logger logr.Logger, cmdList [][]string) error {
 	for _, cmd := range cmdList {
-		if err := execute(cmd, nil); err != nil {
-			panic(err.Error())
+		if err := execute(ctx, cmd); err != nil {
+			return fmt.Errorf("%v failed: %w", cmd, err)
 		}
 
 		logger.Info("Execution completed", "cmd", cmd)
 	}
+
+	return nil
 }
 
 func isVolInitialisationNeeded(logger logr.Logger, initializedVolumes []string, volName,
@@ -263,16 +243,14 @@ func isVolInitialisationNeeded(logger logr.Logger, initializedVolumes []string,
 }
 
 func (initp *InitParams) initVolumes(ctx context.Context, pod *corev1.Pod,
-	initializedVolumes []string) ([]string, error) {
-	var (
-		wg       sync.WaitGroup
-		needInit bool
-	)
+	initializedVolumes []string, progress *wipeProgress) ([]string, error) {
+	var needInit bool
 
-	workerThreads := initp.rack.Storage.CleanupThreads
 	persistentVolumes := getPersistentVolumes(getAttachedVolumes(initp.logger, initp.rack))
 	volumeNames := make([]string, 0, len(persistentVolumes))
-	guard := make(chan struct{}, workerThreads)
+
+	pool := newCleanupPool(ctx, initp.logger, initp.rack.Storage.CleanupThreads)
+	defer pool.stop()
 
 	initializedVolumes = removeOldFormattedVolumeName(initializedVolumes)
 
@@ -289,8 +267,11 @@ func (initp *InitParams) initVolumes(ctx context.Context, pod *corev1.Pod,
 			continue
 		}
 
-		volume := newVolume(initp.podName, vol)
+		volume := initp.newVolume(vol)
+		volume.pvcUID = pvcUID
 		initp.logger.Info(fmt.Sprintf("Starting initialisation for volume=%+v", *volume))
+		initp.recorder.normal(ctx, reasonVolumeInitStarted, "Initializing volume %s (PVC UID %s) with method %s",
+			volume.volumeName, pvcUID, volume.effectiveInitMethod)
 
 		if _, err := os.Stat(volume.getMountPoint()); err != nil {
 			return volumeNames, fmt.Errorf("mounting point %s does not exist %v", volume.getMountPoint(), err)
@@ -298,7 +279,7 @@ func (initp *InitParams) initVolumes(ctx context.Context, pod *corev1.Pod,
 
 		switch volume.volumeMode {
 		case string(corev1.PersistentVolumeBlock):
-			if err := initp.cleanBlockVolume(volume, &wg, guard, true); err != nil {
+			if err := initp.cleanBlockVolume(volume, pool, true, progress); err != nil {
 				return volumeNames, err
 			}
 
@@ -307,7 +288,7 @@ func (initp *InitParams) initVolumes(ctx context.Context, pod *corev1.Pod,
 			case string(asdbv1.AerospikeVolumeMethodDeleteFiles):
 				find := []string{"find", volume.getMountPoint(), "-type", "f", "-delete"}
 
-				err := execute(find, nil)
+				err := execute(ctx, find)
 				if err != nil {
 					initp.logger.Error(err, "Failed to run find command")
 				}
@@ -328,8 +309,15 @@ func (initp *InitParams) initVolumes(ctx context.Context, pod *corev1.Pod,
 		volumeNames = append(volumeNames, fmt.Sprintf("%s@%s", volume.volumeName, pvcUID))
 	}
 
-	close(guard)
-	wg.Wait()
+	if err := pool.wait(); err != nil {
+		initp.recorder.warning(ctx, reasonVolumeInitFailed, "Volume initialization failed: %v", err)
+		return nil, fmt.Errorf("failed to initialize volumes: %w", err)
+	}
+
+	if len(volumeNames) != 0 {
+		initp.recorder.normal(ctx, reasonVolumeInitCompleted, "Initialized volumes %v", volumeNames)
+		initp.report.addInitializedVolumes(volumeNames...)
+	}
 
 	volumeNames = append(volumeNames, initializedVolumes...)
 	initp.logger.Info("Extended initialised volume list", "initializedVolumes", volumeNames)
@@ -392,11 +380,12 @@ func remove(s []string, r string) []string {
 	return s
 }
 
-func (initp *InitParams) cleanDirtyVolumes(dirtyVolumes, nsDevicePaths []string) ([]string, error) {
-	var wg sync.WaitGroup
+func (initp *InitParams) cleanDirtyVolumes(ctx context.Context, dirtyVolumes, nsDevicePaths []string,
+	progress *wipeProgress) ([]string, error) {
+	pool := newCleanupPool(ctx, initp.logger, initp.rack.Storage.CleanupThreads)
+	defer pool.stop()
 
-	workerThreads := initp.rack.Storage.CleanupThreads
-	guard := make(chan struct{}, workerThreads)
+	var cleanedVolumes []string
 
 	persistentVolumes := getPersistentVolumes(getAttachedVolumes(initp.logger, initp.rack))
 	for volIndex := range persistentVolumes {
@@ -408,32 +397,41 @@ func (initp *InitParams) cleanDirtyVolumes(dirtyVolumes, nsDevicePaths []string)
 
 		initp.logger.Info(fmt.Sprintf("Cleaning dirty volume=%s", vol.Name))
 
-		volume := newVolume(initp.podName, vol)
+		volume := initp.newVolume(vol)
 		if volume.volumeMode == string(corev1.PersistentVolumeBlock) {
 			if _, err := os.Stat(volume.getMountPoint()); err != nil {
 				return dirtyVolumes, fmt.Errorf("mounting point %s does not exist %v", volume.getMountPoint(), err)
 			}
 
-			if err := initp.cleanBlockVolume(volume, &wg, guard, false); err != nil {
+			initp.recorder.normal(ctx, reasonDirtyVolumeCleanup, "Cleaning dirty volume %s with method %s",
+				volume.volumeName, volume.effectiveWipeMethod)
+
+			if err := initp.cleanBlockVolume(volume, pool, false, progress); err != nil {
 				return dirtyVolumes, err
 			}
 
 			dirtyVolumes = remove(dirtyVolumes, volume.volumeName)
+			cleanedVolumes = append(cleanedVolumes, volume.volumeName)
 		}
 	}
 
-	close(guard)
-	wg.Wait()
+	if err := pool.wait(); err != nil {
+		initp.recorder.warning(ctx, reasonDirtyVolumeCleanupFailed, "Dirty volume cleanup failed: %v", err)
+		return dirtyVolumes, fmt.Errorf("failed to clean dirty volumes: %w", err)
+	}
+
+	initp.report.addCleanedVolumes(cleanedVolumes...)
 	initp.logger.Info("All cleanup jobs finished successfully")
 
 	return dirtyVolumes, nil
 }
 
-func (initp *InitParams) wipeVolumes(dirtyVolumes, nsDevicePaths, nsFilePaths []string) ([]string, error) {
-	var wg sync.WaitGroup
+func (initp *InitParams) wipeVolumes(ctx context.Context, dirtyVolumes, nsDevicePaths, nsFilePaths []string,
+	progress *wipeProgress) ([]string, error) {
+	pool := newCleanupPool(ctx, initp.logger, initp.rack.Storage.CleanupThreads)
+	defer pool.stop()
 
-	workerThreads := initp.rack.Storage.CleanupThreads
-	guard := make(chan struct{}, workerThreads)
+	var wipedVolumes []string
 
 	persistentVolumes := getPersistentVolumes(getAttachedVolumes(initp.logger, initp.rack))
 	for volIndex := range persistentVolumes {
@@ -442,7 +440,7 @@ func (initp *InitParams) wipeVolumes(dirtyVolumes, nsDevicePaths, nsFilePaths []
 			continue
 		}
 
-		volume := newVolume(initp.podName, vol)
+		volume := initp.newVolume(vol)
 		switch volume.volumeMode {
 		case string(corev1.PersistentVolumeBlock):
 			if utils.ContainsString(nsDevicePaths, volume.aerospikeVolumePath) {
@@ -452,11 +450,15 @@ func (initp *InitParams) wipeVolumes(dirtyVolumes, nsDevicePaths, nsFilePaths []
 					return dirtyVolumes, fmt.Errorf("mounting point %s does not exist %v", volume.getMountPoint(), err)
 				}
 
-				if err := initp.cleanBlockVolume(volume, &wg, guard, false); err != nil {
+				initp.recorder.normal(ctx, reasonVolumeWipeStarted, "Wiping volume %s with method %s",
+					volume.volumeName, volume.effectiveWipeMethod)
+
+				if err := initp.cleanBlockVolume(volume, pool, false, progress); err != nil {
 					return dirtyVolumes, err
 				}
 
 				dirtyVolumes = remove(dirtyVolumes, volume.volumeName)
+				wipedVolumes = append(wipedVolumes, volume.volumeName)
 			}
 		case string(corev1.PersistentVolumeFilesystem):
 			if volume.effectiveWipeMethod == string(asdbv1.AerospikeVolumeMethodDeleteFiles) {
@@ -464,6 +466,9 @@ func (initp *InitParams) wipeVolumes(dirtyVolumes, nsDevicePaths, nsFilePaths []
 					return dirtyVolumes, fmt.Errorf("mounting point %s does not exist %v", volume.getMountPoint(), err)
 				}
 
+				initp.recorder.normal(ctx, reasonVolumeWipeStarted, "Wiping namespace files of volume %s with method %s",
+					volume.volumeName, volume.effectiveWipeMethod)
+
 				for _, nsFilePath := range nsFilePaths {
 					if strings.HasPrefix(nsFilePath, volume.aerospikeVolumePath) {
 						_, fileName := filepath.Split(nsFilePath)
@@ -481,6 +486,8 @@ func (initp *InitParams) wipeVolumes(dirtyVolumes, nsDevicePaths, nsFilePaths []
 						}
 					}
 				}
+
+				wipedVolumes = append(wipedVolumes, volume.volumeName)
 			} else {
 				return dirtyVolumes, fmt.Errorf("invalid effective_wipe_method %s", volume.effectiveWipeMethod)
 			}
@@ -490,15 +497,19 @@ func (initp *InitParams) wipeVolumes(dirtyVolumes, nsDevicePaths, nsFilePaths []
 		}
 	}
 
-	close(guard)
-	wg.Wait()
+	if err := pool.wait(); err != nil {
+		initp.recorder.warning(ctx, reasonVolumeWipeFailed, "Volume wipe failed: %v", err)
+		return dirtyVolumes, fmt.Errorf("failed to wipe volumes: %w", err)
+	}
+
+	initp.report.addWipedVolumes(wipedVolumes...)
 	initp.logger.Info("All wipe jobs finished successfully")
 
 	return dirtyVolumes, nil
 }
 
-func (initp *InitParams) cleanBlockVolume(volume *Volume, wg *sync.WaitGroup, guard chan struct{},
-	isInitializing bool) error {
+func (initp *InitParams) cleanBlockVolume(volume *Volume, pool *cleanupPool,
+	isInitializing bool, progress *wipeProgress) error {
 	effectiveMethod := volume.effectiveWipeMethod
 
 	if isInitializing {
@@ -509,37 +520,38 @@ func (initp *InitParams) cleanBlockVolume(volume *Volume, wg *sync.WaitGroup, gu
 
 	switch effectiveMethod {
 	case string(asdbv1.AerospikeVolumeMethodDD):
-		dd := []string{string(asdbv1.AerospikeVolumeMethodDD),
-			"if=/dev/zero", "of=" + volume.getMountPoint(), "bs=1M"}
-
-		wg.Add(1)
+		// Only volume initializations are journaled, resume from the last checkpoint if any.
+		pvcUID := ""
+		if isInitializing {
+			pvcUID = volume.pvcUID
+		}
 
-		guard <- struct{}{}
+		zeroer := newDeviceZeroer(volume.getMountPoint(),
+			progress.resumeOffset(volume.volumeName, pvcUID, effectiveMethod), 0)
+		progress.track(volume.volumeName, effectiveMethod, pvcUID, zeroer)
 
-		go runDD(initp.logger, dd, wg, guard)
+		pool.submit(volume.volumeName, effectiveMethod, func(ctx context.Context) error {
+			return runZero(ctx, initp.logger, zeroer)
+		})
 
-		initp.logger.Info(fmt.Sprintf("Command submitted %v for volume=%+v", dd, *volume))
+		initp.logger.Info(fmt.Sprintf("Zeroing submitted for volume=%+v", *volume))
 
 	case string(asdbv1.AerospikeVolumeMethodHeaderCleanup):
-		ddHeader := []string{string(asdbv1.AerospikeVolumeMethodDD),
-			"if=/dev/zero", "of=" + volume.getMountPoint(), "bs=1M", "count=8"}
-
-		wg.Add(1)
+		zeroer := newDeviceZeroer(volume.getMountPoint(), 0, headerCleanupSize)
+		progress.track(volume.volumeName, effectiveMethod, "", zeroer)
 
-		guard <- struct{}{}
+		pool.submit(volume.volumeName, effectiveMethod, func(ctx context.Context) error {
+			return runZero(ctx, initp.logger, zeroer)
+		})
 
-		go runDD(initp.logger, ddHeader, wg, guard)
-
-		initp.logger.Info(fmt.Sprintf("Command submitted %v for volume=%+v", ddHeader, *volume))
+		initp.logger.Info(fmt.Sprintf("Header zeroing submitted for volume=%+v", *volume))
 
 	case string(asdbv1.AerospikeVolumeMethodBlkdiscard):
 		blkdiscard := [][]string{{string(asdbv1.AerospikeVolumeMethodBlkdiscard), volume.getMountPoint()}}
 
-		wg.Add(1)
-
-		guard <- struct{}{}
-
-		go runBlkdiscard(initp.logger, blkdiscard, wg, guard)
+		pool.submit(volume.volumeName, effectiveMethod, func(ctx context.Context) error {
+			return runBlkdiscard(ctx, initp.logger, blkdiscard)
+		})
 
 		initp.logger.Info(fmt.Sprintf("Command submitted %v for volume=%+v", blkdiscard, *volume))
 
@@ -549,11 +561,9 @@ func (initp *InitParams) cleanBlockVolume(volume *Volume, wg *sync.WaitGroup, gu
 			{string(asdbv1.AerospikeVolumeMethodBlkdiscard), "-z", "--length", "8MiB", volume.getMountPoint()},
 		}
 
-		wg.Add(1)
-
-		guard <- struct{}{}
-
-		go runBlkdiscard(initp.logger, blkdiscardCmds, wg, guard)
+		pool.submit(volume.volumeName, effectiveMethod, func(ctx context.Context) error {
+			return runBlkdiscard(ctx, initp.logger, blkdiscardCmds)
+		})
 
 		initp.logger.Info(fmt.Sprintf("Commands submitted %v for volume=%+v", blkdiscardCmds, *volume))
 
@@ -579,6 +589,8 @@ func (initp *InitParams) manageVolumesAndUpdateStatus(ctx context.Context, resta
 		return err
 	}
 
+	initp.recorder.setPodUID(pod.UID)
+
 	podImage, podInitImage := initp.getPodImages(pod)
 	prevImage := ""
 
@@ -597,40 +609,12 @@ func (initp *InitParams) manageVolumesAndUpdateStatus(ctx context.Context, resta
 	if restartType == "podRestart" {
 		var err error
 
-		initializedVolumes, err = initp.initVolumes(ctx, pod, initializedVolumes)
-		if err != nil {
-			return err
-		}
-
-		nsDevicePaths, nsFilePaths := initp.getNamespaceVolumePaths()
-
-		initp.logger.Info("Checking if volumes should be wiped", "podname", initp.podName)
-
-		if prevImage != "" {
-			prevMajorVer, imageErr := getImageVersion(prevImage)
-			if imageErr != nil {
-				return imageErr
-			}
-
-			nextMajorVer, imageErr := getImageVersion(podImage)
-			if imageErr != nil {
-				return imageErr
-			}
+		endPhase := initp.report.startPhase("ManageVolumes")
+		initializedVolumes, dirtyVolumes, err = initp.manageVolumes(ctx, pod, prevImage, podImage,
+			initializedVolumes, dirtyVolumes)
 
-			if (nextMajorVer >= baseWipeVersion && baseWipeVersion > prevMajorVer) ||
-				(nextMajorVer < baseWipeVersion && baseWipeVersion <= prevMajorVer) {
-				dirtyVolumes, err = initp.wipeVolumes(dirtyVolumes, nsDevicePaths, nsFilePaths)
-				if err != nil {
-					return err
-				}
-			} else {
-				initp.logger.Info("Volumes should not be wiped", "nextMajorVer", nextMajorVer, "prevMajorVer", prevMajorVer)
-			}
-		} else {
-			initp.logger.Info("Volumes should not be wiped")
-		}
+		endPhase(err)
 
-		dirtyVolumes, err = initp.cleanDirtyVolumes(dirtyVolumes, nsDevicePaths)
 		if err != nil {
 			return err
 		}
@@ -641,47 +625,156 @@ func (initp *InitParams) manageVolumesAndUpdateStatus(ctx context.Context, resta
 	metadata.InitImage = podInitImage
 	metadata.InitializedVolumes = initializedVolumes
 	metadata.DirtyVolumes = dirtyVolumes
-	metadata.DynamicConfigUpdateStatus = ""
+	metadata.DynamicConfigUpdateStatus = initp.dynamicConfigStatus
 	metadata.RackIDOverridden = ptr.Deref(initp.aeroCluster.Spec.EnableRackIDOverride, false)
 
-	data, err := os.ReadFile(aerospikeConf)
+	data, err := initp.annotatedConf()
 	if err != nil {
 		return err
 	}
 
-	if err := retry.OnError(retry.DefaultBackoff, func(_ error) bool {
-		// Customize the error check for retrying, return true to retry, false to stop retrying
-		return true
-	}, func() error {
-		if err := initp.k8sClient.Get(ctx, podNamespacedName, pod); err != nil {
-			return err
+	confAnnotations, err := initp.confAnnotations(ctx, pod, data)
+	if err != nil {
+		return err
+	}
+
+	annotations := make(map[string]string, len(confAnnotations))
+	staleAnnotations := make([]string, 0, len(confAnnotations))
+
+	for key, value := range confAnnotations {
+		if value != "" {
+			annotations[key] = value
+		} else if _, ok := pod.Annotations[key]; ok {
+			staleAnnotations = append(staleAnnotations, key)
 		}
+	}
+
+	if err := initp.applyPodAnnotations(ctx, confFieldManager, annotations); err != nil {
+		return err
+	}
+
+	// Annotations of the other storage modes may be owned by another field manager, remove them explicitly.
+	if err := initp.removePodAnnotations(ctx, staleAnnotations...); err != nil {
+		return err
+	}
+
+	initp.logger.Info("Patched pod annotation successfully", "podname", initp.podName)
+
+	initp.logger.Info("Updating pod status in CR", "podname", initp.podName)
+
+	endPhase := initp.report.startPhase("UpdateStatus")
+	err = initp.updateStatus(ctx, metadata)
+
+	endPhase(err)
+
+	if err != nil {
+		initp.recorder.warning(ctx, reasonStatusUpdateFailed, "Failed to update pod status in AerospikeCluster: %v", err)
+		return err
+	}
 
-		if pod.Annotations == nil {
-			pod.Annotations = make(map[string]string)
+	initp.report.setEndpoints(initp.Endpoints())
+
+	initp.recorder.normal(ctx, reasonStatusUpdated,
+		"Updated pod status in AerospikeCluster after %s, initialized volumes %v, dirty volumes %v",
+		restartType, initializedVolumes, dirtyVolumes)
+
+	// Initialized volumes are recorded in the CR status, the init journal is not needed anymore.
+	// Removing it is best-effort, it is attempted again on the next pod restart.
+	if restartType == "podRestart" {
+		if err := initp.patchPodAnnotation(ctx, volumeCheckpointAnnotation, nil); err != nil {
+			initp.logger.Error(err, "Failed to remove volume init checkpoint", "podname", initp.podName)
+		} else {
+			initp.logger.Info("Removed volume init checkpoint", "podname", initp.podName)
 		}
+	}
 
-		pod.Annotations["aerospikeConf"] = string(data)
+	return nil
+}
 
-		// Patch the resource
-		if err := initp.k8sClient.Update(ctx, pod); err != nil {
-			return err
+// annotatedConf returns the aerospike.conf published for the pod, with the secret placeholders instead of
+// the secret values. Parameters are not masked, the operator compares it with the aerospikeConfig of the cluster.
+func (initp *InitParams) annotatedConf() (string, error) {
+	if initp.renderedConf != "" {
+		return initp.renderedConf, nil
+	}
+
+	// Not rendered by this run, the backup of the current aerospike.conf holds its placeholders.
+	backupDir, err := initp.currentConfBackup()
+	if err != nil {
+		return "", err
+	}
+
+	confFile := initp.paths.aerospikeConf()
+	if backupDir != "" {
+		confFile = filepath.Join(backupDir, aerospikeConfFile)
+	}
+
+	data, err := os.ReadFile(confFile)
+	if err != nil {
+		return "", err
+	}
+
+	return initp.redactSecretValues(string(data)), nil
+}
+
+// manageVolumes initializes, wipes and cleans the pod volumes as needed after a pod restart.
+// Progress of the volume wipes is published on the pod while they run.
+func (initp *InitParams) manageVolumes(ctx context.Context, pod *corev1.Pod, prevImage, podImage string,
+	initializedVolumes, dirtyVolumes []string) (newInitializedVolumes, newDirtyVolumes []string, err error) {
+	progress := initp.newWipeProgress(pod)
+
+	stopProgress := progress.start(ctx)
+	defer stopProgress()
+
+	initializedVolumes, err = initp.initVolumes(ctx, pod, initializedVolumes, progress)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nsDevicePaths, nsFilePaths := initp.getNamespaceVolumePaths()
+
+	initp.logger.Info("Checking if volumes should be wiped", "podname", initp.podName)
+
+	if prevImage != "" {
+		prevMajorVer, imageErr := getImageVersion(prevImage)
+		if imageErr != nil {
+			return nil, nil, imageErr
 		}
 
-		initp.logger.Info("Patched/updated pod annotation successfully", "podname", initp.podName)
+		nextMajorVer, imageErr := getImageVersion(podImage)
+		if imageErr != nil {
+			return nil, nil, imageErr
+		}
 
-		return nil
-	}); err != nil {
-		return err
+		if (nextMajorVer >= baseWipeVersion && baseWipeVersion > prevMajorVer) ||
+			(nextMajorVer < baseWipeVersion && baseWipeVersion <= prevMajorVer) {
+			dirtyVolumes, err = initp.wipeVolumes(ctx, dirtyVolumes, nsDevicePaths, nsFilePaths, progress)
+			if err != nil {
+				return nil, nil, err
+			}
+		} else {
+			initp.logger.Info("Volumes should not be wiped", "nextMajorVer", nextMajorVer, "prevMajorVer", prevMajorVer)
+			initp.recorder.normal(ctx, reasonVolumeWipeSkipped,
+				"Volume wipe skipped, image major version %d to %d does not cross version %d",
+				prevMajorVer, nextMajorVer, baseWipeVersion)
+		}
+	} else {
+		initp.logger.Info("Volumes should not be wiped")
+		initp.recorder.normal(ctx, reasonVolumeWipeSkipped, "Volume wipe skipped, pod has no previous image in status")
 	}
 
-	initp.logger.Info("Updating pod status in CR", "podname", initp.podName)
+	dirtyVolumes, err = initp.cleanDirtyVolumes(ctx, dirtyVolumes, nsDevicePaths, progress)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	return initp.updateStatus(ctx, metadata)
+	return initializedVolumes, dirtyVolumes, nil
 }
 
 func (initp *InitParams) updateStatus(ctx context.Context,
 	metadata *asdbv1.AerospikePodStatus) error {
+	configMapDir := initp.paths.configMapDir()
+
 	confHashBytes, err := os.ReadFile(filepath.Join(configMapDir, "aerospikeConfHash"))
 	if err != nil {
 		return fmt.Errorf("failed to read aerospikeConfHash file %v", err)
@@ -689,6 +782,13 @@ func (initp *InitParams) updateStatus(ctx context.Context,
 
 	metadata.AerospikeConfigHash = string(confHashBytes)
 
+	// The server runs with the previous config after a rollback, or only part of the new one after a failed
+	// dynamic config update. The operator must see the new one as not applied.
+	if prevStatus, ok := initp.aeroCluster.Status.Pods[initp.podName]; ok &&
+		(initp.confRolledBack || initp.dynamicConfigStatus != asdbv1.Empty) {
+		metadata.AerospikeConfigHash = prevStatus.AerospikeConfigHash
+	}
+
 	networkPolicyHashBytes, err := os.ReadFile(filepath.Join(configMapDir, "networkPolicyHash"))
 	if err != nil {
 		return fmt.Errorf("failed to read networkPolicyHash file %v", err)
@@ -708,27 +808,69 @@ func (initp *InitParams) updateStatus(ctx context.Context,
 	metadata.Aerospike.TLSAccessEndpoints = initp.getEndpoints(tlsAccess)
 	metadata.Aerospike.TLSAlternateAccessEndpoints = initp.getEndpoints(tlsAlternateAccess)
 
-	patches := make([]jp.JsonPatchOperation, 0, 1)
-	patch := jp.JsonPatchOperation{
-		Operation: "replace",
-		Path:      "/status/pods/" + initp.podName,
-		Value:     *metadata,
+	clusterNamespacedName := getNamespacedName(initp.aeroCluster.Name, initp.aeroCluster.Namespace)
+
+	// The patch operation depends on the current status, which is fetched again on every attempt.
+	// The patch is rejected with a conflict if the status changed since.
+	if err := retry.OnError(retry.DefaultBackoff, isTransientError, func() error {
+		aeroCluster := &asdbv1.AerospikeCluster{}
+		if err := initp.k8sClient.Get(ctx, clusterNamespacedName, aeroCluster); err != nil {
+			return err
+		}
+
+		jsonPatchJSON, err := json.Marshal(podStatusPatch(aeroCluster, initp.podName, metadata))
+		if err != nil {
+			return fmt.Errorf("error creating json-patch : %v", err)
+		}
+
+		return initp.k8sClient.Status().Patch(
+			ctx, aeroCluster, client.RawPatch(types.JSONPatchType, jsonPatchJSON), client.FieldOwner("pod"),
+		)
+	}); err != nil {
+		return fmt.Errorf("error updating status of pod %s in AerospikeCluster %s: %v", initp.podName,
+			clusterNamespacedName, err)
 	}
-	patches = append(patches, patch)
 
-	jsonPatchJSON, err := json.Marshal(patches)
-	if err != nil {
-		return fmt.Errorf("error creating json-patch : %v", err)
+	return nil
+}
+
+// podStatusPatch returns the JSON patch setting the pod status. The pod entry is replaced if present,
+// added otherwise, and status.pods itself is added if it is null. The patch carries the resourceVersion
+// of the given cluster, so that it is rejected with a conflict instead of being applied to a status
+// changed since, e.g. overwriting a status.pods added concurrently.
+func podStatusPatch(aeroCluster *asdbv1.AerospikeCluster, podName string,
+	metadata *asdbv1.AerospikePodStatus) []jp.JsonPatchOperation {
+	patch := []jp.JsonPatchOperation{
+		{
+			Operation: "replace",
+			Path:      "/metadata/resourceVersion",
+			Value:     aeroCluster.ResourceVersion,
+		},
 	}
 
-	constantPatch := client.RawPatch(types.JSONPatchType, jsonPatchJSON)
-	if err = initp.k8sClient.Status().Patch(
-		ctx, initp.aeroCluster, constantPatch, client.FieldOwner("pod"),
-	); err != nil {
-		return fmt.Errorf("error updating status: %v", err)
+	if aeroCluster.Status.Pods == nil {
+		return append(patch, jp.JsonPatchOperation{
+			Operation: "add",
+			Path:      "/status/pods",
+			Value:     map[string]asdbv1.AerospikePodStatus{podName: *metadata},
+		})
 	}
 
-	return nil
+	operation := "add"
+	if _, ok := aeroCluster.Status.Pods[podName]; ok {
+		operation = "replace"
+	}
+
+	return append(patch, jp.JsonPatchOperation{
+		Operation: operation,
+		Path:      "/status/pods/" + escapeJSONPointer(podName),
+		Value:     *metadata,
+	})
+}
+
+// escapeJSONPointer escapes a JSON Pointer reference token as per RFC 6901.
+func escapeJSONPointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
 }
 
 func (initp *InitParams) getEndpoints(addressType string) []string {
@@ -761,7 +903,10 @@ func (initp *InitParams) getEndpoints(addressType string) []string {
 
 		switch {
 		case host == nil:
-			continue
+			// DNS names and load balancer ingress hostnames.
+			if addr != "" && len(validation.IsDNS1123Subdomain(addr)) == 0 {
+				endPoints = append(endPoints, addr+":"+strconv.Itoa(int(globalPort)))
+			}
 		case host.To4() != nil:
 			accessPoint := host.String() + ":" + strconv.Itoa(int(globalPort))
 			endPoints = append(endPoints, accessPoint)
@@ -0,0 +1,166 @@
+package pkg
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+	jp "gomodules.xyz/jsonpatch/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	asdbv1 "github.com/aerospike/aerospike-kubernetes-operator/v4/api/v1"
+)
+
+func TestAnnotatedConf(t *testing.T) {
+	const (
+		placeholderConf = "network {\n    tls tls1 {\n        key-file-password    ${secret:tls/password}\n    }\n}\n"
+		resolvedConf    = "network {\n    tls tls1 {\n        key-file-password    s3cr3t\n    }\n}\n"
+		maskedConf      = "network {\n    tls tls1 {\n        key-file-password    " + redactedValue + "\n    }\n}\n"
+		inlineConf      = "network {\n    tls tls1 {\n        key-file-password    password\n    }\n}\n"
+	)
+
+	tests := []struct {
+		name         string
+		renderedConf string
+		// backupConf is the placeholder conf backed up with resolvedConf, no backup if empty.
+		backupConf   string
+		secretValues []string
+		want         string
+	}{
+		{
+			name:         "rendered conf is not masked",
+			renderedConf: inlineConf,
+			want:         inlineConf,
+		},
+		{
+			name:       "placeholders from the backup",
+			backupConf: placeholderConf,
+			want:       placeholderConf,
+		},
+		{
+			name:         "resolved conf without backup",
+			secretValues: []string{"s3cr3t"},
+			want:         maskedConf,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initp := &InitParams{
+				logger:       logr.Discard(),
+				paths:        Paths{ConfigVolume: t.TempDir()},
+				renderedConf: tt.renderedConf,
+				secretValues: tt.secretValues,
+			}
+
+			if err := os.WriteFile(initp.paths.aerospikeConf(), []byte(resolvedConf), 0600); err != nil {
+				t.Fatalf("failed to write aerospike conf: %v", err)
+			}
+
+			if tt.backupConf != "" {
+				if _, err := initp.saveConfBackup([]byte(tt.backupConf), []byte(resolvedConf), nil); err != nil {
+					t.Fatalf("saveConfBackup() error = %v", err)
+				}
+			}
+
+			got, err := initp.annotatedConf()
+			if err != nil {
+				t.Fatalf("annotatedConf() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("annotatedConf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPodStatusPatch(t *testing.T) {
+	metadata := &asdbv1.AerospikePodStatus{PodIP: "10.0.0.1", AerospikeConfigHash: "hash"}
+	resourceVersion := jp.JsonPatchOperation{Operation: "replace", Path: "/metadata/resourceVersion", Value: "42"}
+
+	tests := []struct {
+		name     string
+		podName  string
+		pods     map[string]asdbv1.AerospikePodStatus
+		wantLast jp.JsonPatchOperation
+	}{
+		{
+			name:    "null pods",
+			podName: "aerospike-0-0",
+			wantLast: jp.JsonPatchOperation{
+				Operation: "add",
+				Path:      "/status/pods",
+				Value:     map[string]asdbv1.AerospikePodStatus{"aerospike-0-0": *metadata},
+			},
+		},
+		{
+			name:     "new pod",
+			podName:  "aerospike-0-0",
+			pods:     map[string]asdbv1.AerospikePodStatus{"aerospike-0-1": {}},
+			wantLast: jp.JsonPatchOperation{Operation: "add", Path: "/status/pods/aerospike-0-0", Value: *metadata},
+		},
+		{
+			name:    "empty pods",
+			podName: "aerospike-0-0",
+			pods:    map[string]asdbv1.AerospikePodStatus{},
+			wantLast: jp.JsonPatchOperation{
+				Operation: "add", Path: "/status/pods/aerospike-0-0", Value: *metadata,
+			},
+		},
+		{
+			name:    "existing pod",
+			podName: "aerospike-0-0",
+			pods:    map[string]asdbv1.AerospikePodStatus{"aerospike-0-0": {PodIP: "10.0.0.2"}},
+			wantLast: jp.JsonPatchOperation{
+				Operation: "replace", Path: "/status/pods/aerospike-0-0", Value: *metadata,
+			},
+		},
+		{
+			name:    "escaped pod name",
+			podName: "a/b~c",
+			pods:    map[string]asdbv1.AerospikePodStatus{"a/b~c": {}},
+			wantLast: jp.JsonPatchOperation{
+				Operation: "replace", Path: "/status/pods/a~1b~0c", Value: *metadata,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aeroCluster := &asdbv1.AerospikeCluster{
+				ObjectMeta: metav1.ObjectMeta{ResourceVersion: "42"},
+				Status:     asdbv1.AerospikeClusterStatus{Pods: tt.pods},
+			}
+
+			want := []jp.JsonPatchOperation{resourceVersion, tt.wantLast}
+
+			if got := podStatusPatch(aeroCluster, tt.podName, metadata); !reflect.DeepEqual(got, want) {
+				t.Errorf("podStatusPatch() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestEscapeJSONPointer(t *testing.T) {
+	tests := []struct {
+		token string
+		want  string
+	}{
+		{token: "aerospike-0-0", want: "aerospike-0-0"},
+		{token: "a/b", want: "a~1b"},
+		{token: "a~b", want: "a~0b"},
+		{token: "~1", want: "~01"},
+		{token: "/~", want: "~1~0"},
+		{token: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			if got := escapeJSONPointer(tt.token); got != tt.want {
+				t.Errorf("escapeJSONPointer(%q) = %q, want %q", tt.token, got, tt.want)
+			}
+		})
+	}
+}
@@ -4,16 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	netattach "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	asdbv1 "github.com/aerospike/aerospike-kubernetes-operator/v4/api/v1"
@@ -41,6 +43,12 @@ type networkInfo struct {
 	configuredAlterAccessIP string
 	serviceTLSName          string
 
+	// podIPs, internalIPs and externalIPs hold the addresses of each IP family on dual-stack clusters.
+	podIPs          ipFamilies
+	internalIPs     ipFamilies
+	externalIPs     ipFamilies
+	addressFamilies map[string]addressFamily
+
 	customAccessNetworkIPs             []string
 	customTLSAccessNetworkIPs          []string
 	customAlternateAccessNetworkIPs    []string
@@ -48,6 +56,13 @@ type networkInfo struct {
 	customFabricNetworkIPs             []string
 	customTLSFabricNetworkIPs          []string
 
+	loadBalancerAddresses      []string
+	loadBalancerServicePort    int32
+	loadBalancerServiceTLSPort int32
+
+	// dnsNames holds the DNS name of each access type using the dnsName network type.
+	dnsNames map[string]string
+
 	globalAddressesAndPorts globalAddressesAndPorts
 
 	fabricPort           int32
@@ -71,6 +86,9 @@ const (
 	configuredAccessIPLabel          = "aerospike.com/configured-access-address"
 	configuredAlternateAccessIPLabel = "aerospike.com/configured-alternate-access-address"
 	networkStatusAnnotation          = "k8s.v1.cni.cncf.io/network-status"
+
+	customNetworkPollInterval    = time.Second
+	customNetworkMaxPollInterval = 15 * time.Second
 )
 
 func getNamespacedName(name, namespace string) types.NamespacedName {
@@ -90,18 +108,30 @@ func getCluster(ctx context.Context, k8sClient client.Client,
 	return aeroCluster, nil
 }
 
-func (initp *InitParams) setNetworkInfo(ctx context.Context) error {
+func (initp *InitParams) setNetworkInfo(ctx context.Context, hostIP, podIP string) error {
 	initp.logger.Info("Gathering network related info")
 
+	networkPolicy, err := initp.getAccessNetworkPolicy()
+	if err != nil {
+		return err
+	}
+
 	initp.networkInfo = &networkInfo{
 		multiPodPerHost: asdbv1.GetBool(initp.aeroCluster.Spec.PodSpec.MultiPodPerHost),
-		networkPolicy:   initp.aeroCluster.Spec.AerospikeNetworkPolicy,
+		networkPolicy:   networkPolicy,
 		hostNetwork:     initp.aeroCluster.Spec.PodSpec.HostNetwork,
-		hostIP:          os.Getenv("MY_HOST_IP"),
-		podIP:           os.Getenv("MY_POD_IP"),
-		internalIP:      os.Getenv("MY_HOST_IP"),
+		hostIP:          hostIP,
+		podIP:           podIP,
+		internalIP:      hostIP,
 	}
 
+	addressFamilies, err := initp.getAddressFamilies()
+	if err != nil {
+		return err
+	}
+
+	initp.networkInfo.addressFamilies = addressFamilies
+
 	asConfig := initp.aeroCluster.Spec.AerospikeConfig
 
 	if serviceTLSName, serviceTLSPort := asdbv1.GetServiceTLSNameAndPort(asConfig); serviceTLSPort != nil {
@@ -278,7 +308,7 @@ func (initp *InitParams) makeWorkDir() error {
 	// it means that user has not provided any workDir in storage.volumes spec.
 	defaultWorkDirectory := "/opt/aerospike"
 	if initp.workDir != "" && initp.workDir != defaultWorkDirectory {
-		defaultWorkDir := filepath.Join("workdir", "filesystem-volumes", initp.workDir)
+		defaultWorkDir := filepath.Join(initp.paths.FileSystemVolumesDir, initp.workDir)
 
 		requiredDirs := [2]string{"smd", "usr/udf/lua"}
 		for _, d := range requiredDirs {
@@ -297,12 +327,25 @@ func (initp *InitParams) makeWorkDir() error {
 func (initp *InitParams) setIPAndPorts(ctx context.Context) (err error) {
 	netInfo := initp.networkInfo
 
+	pod := &corev1.Pod{}
+
+	err = initp.k8sClient.Get(ctx, types.NamespacedName{
+		Name:      initp.podName,
+		Namespace: initp.namespace,
+	}, pod)
+	if err != nil {
+		return err
+	}
+
+	// Record events against the pod UID from here on, so that they show up in kubectl describe pod.
+	initp.recorder.setPodUID(pod.UID)
+
 	// Sets up port related variables.
 	// User service ports only when MultiPodPerHost is true and node network is defined in NetworkPolicy
 	if asdbv1.GetBool(initp.aeroCluster.Spec.PodSpec.MultiPodPerHost) && initp.isNodeNetwork() {
 		if netInfo.mappedServicePort, netInfo.mappedServiceTLSPort, netInfo.mappedAdminPort,
 			netInfo.mappedAdminTLSPort, err = getPorts(
-			ctx, initp.k8sClient, initp.aeroCluster.Namespace, initp.podName); err != nil {
+			ctx, initp.logger, initp.k8sClient, initp.aeroCluster.Namespace, initp.podName); err != nil {
 			return err
 		}
 	} else {
@@ -314,25 +357,87 @@ func (initp *InitParams) setIPAndPorts(ctx context.Context) (err error) {
 	}
 
 	if initp.isNodeNetwork() {
-		if netInfo.internalIP, netInfo.externalIP, netInfo.configureAccessIP,
-			netInfo.configuredAlterAccessIP, err = getHostIPS(ctx, initp.k8sClient, netInfo.hostIP); err != nil {
+		if netInfo.internalIPs, netInfo.externalIPs, netInfo.configureAccessIP,
+			netInfo.configuredAlterAccessIP, err = getHostIPS(
+			ctx, initp.logger, initp.k8sClient, pod.Spec.NodeName, netInfo.hostIP); err != nil {
 			return err
 		}
+
+		// The node summary gets the addresses of the host IP family.
+		netInfo.internalIP = netInfo.internalIPs.get("", netInfo.hostIP)
+		netInfo.externalIP = netInfo.externalIPs.get("", netInfo.hostIP)
 	}
 
-	pod := &corev1.Pod{}
+	for _, podIP := range pod.Status.PodIPs {
+		netInfo.podIPs.set(podIP.IP)
+	}
 
-	err = initp.k8sClient.Get(ctx, types.NamespacedName{
-		Name:      initp.podName,
-		Namespace: initp.namespace,
-	}, pod)
-	if err != nil {
+	// The primary pod IP takes precedence within its family.
+	netInfo.podIPs.set(netInfo.podIP)
+
+	if initp.usesLoadBalancer() {
+		if netInfo.loadBalancerAddresses, netInfo.loadBalancerServicePort, netInfo.loadBalancerServiceTLSPort,
+			err = initp.getLoadBalancerIngress(ctx); err != nil {
+			return err
+		}
+	}
+
+	if netInfo.dnsNames, err = initp.getDNSNames(ctx, pod); err != nil {
 		return err
 	}
 
 	initp.logger.Info("Gathering custom Interface related info if given")
 
-	// populate custom interface IPs in case of customInterface network type
+	if err := initp.waitForCustomNetworkIPs(ctx, pod); err != nil {
+		return err
+	}
+
+	initp.logger.Info("Gathered custom Interface related info")
+
+	return nil
+}
+
+// waitForCustomNetworkIPs populates the custom interface IPs from the pod network status annotation.
+// The CNI meta-plugin may set the annotation after the init container has started, so the pod is fetched again
+// with backoff until every requested network has IPs.
+func (initp *InitParams) waitForCustomNetworkIPs(ctx context.Context, pod *corev1.Pod) error {
+	waitCtx, cancel := context.WithTimeout(ctx, initp.network.CustomNetworkWaitTimeout)
+	defer cancel()
+
+	delay := customNetworkPollInterval
+
+	for {
+		err := initp.setCustomNetworkIPs(pod)
+		if err == nil {
+			return nil
+		}
+
+		initp.logger.Info("Waiting for custom network status", "reason", err.Error())
+
+		select {
+		case <-waitCtx.Done():
+			return fmt.Errorf("custom network IPs not available within %s: %v",
+				initp.network.CustomNetworkWaitTimeout, err)
+		case <-time.After(wait.Jitter(delay, 0.1)):
+		}
+
+		delay = min(2*delay, customNetworkMaxPollInterval)
+
+		refreshedPod := &corev1.Pod{}
+		if err := initp.k8sClient.Get(waitCtx, getNamespacedName(initp.podName, initp.namespace),
+			refreshedPod); err != nil {
+			initp.logger.Error(err, "Failed to get pod, retrying", "podname", initp.podName)
+			continue
+		}
+
+		pod = refreshedPod
+	}
+}
+
+// setCustomNetworkIPs populates custom interface IPs in case of customInterface network type.
+func (initp *InitParams) setCustomNetworkIPs(pod *corev1.Pod) (err error) {
+	netInfo := initp.networkInfo
+
 	if netInfo.customAccessNetworkIPs, err = parseCustomNetworkIP(netInfo.networkPolicy.AccessType, pod.Annotations,
 		netInfo.networkPolicy.CustomAccessNetworkNames); err != nil {
 		return err
@@ -363,111 +468,149 @@ func (initp *InitParams) setIPAndPorts(ctx context.Context) (err error) {
 		return err
 	}
 
-	initp.logger.Info("Gathered custom Interface related info")
-
 	return nil
 }
 
 // Get tls, info port
-func getPorts(ctx context.Context, k8sClient client.Client, namespace,
+func getPorts(ctx context.Context, logger logr.Logger, k8sClient client.Client, namespace,
 	podName string) (servicePort, serviceTLSPort, adminPort, adminTLSPort int32, err error) {
-	serviceList := &corev1.ServiceList{}
-	listOps := &client.ListOptions{Namespace: namespace}
-
-	err = k8sClient.List(ctx, serviceList, listOps)
-	if err != nil {
+	service, err := getPodService(ctx, logger, k8sClient, namespace, podName)
+	if err != nil || service == nil {
 		return servicePort, serviceTLSPort, adminPort, adminTLSPort, err
 	}
 
-	for idx := range serviceList.Items {
-		service := &serviceList.Items[idx]
-		if service.Name == podName {
-			for _, port := range service.Spec.Ports {
-				switch port.Name {
-				case "service":
-					servicePort = port.NodePort
-				case "tls-service":
-					serviceTLSPort = port.NodePort
-				case "admin":
-					adminPort = port.NodePort
-				case "tls-admin":
-					adminTLSPort = port.NodePort
-				}
-			}
+	for _, port := range service.Spec.Ports {
+		switch port.Name {
+		case "service":
+			servicePort = port.NodePort
+		case "tls-service":
+			serviceTLSPort = port.NodePort
+		case "admin":
+			adminPort = port.NodePort
+		case "tls-admin":
+			adminTLSPort = port.NodePort
+		}
+	}
+
+	return servicePort, serviceTLSPort, adminPort, adminTLSPort, nil
+}
 
-			break
+// getPodService returns the service named after the pod, nil if there is none.
+// The services of the namespace are listed only if the service cannot be read directly.
+func getPodService(ctx context.Context, logger logr.Logger, k8sClient client.Client, namespace,
+	podName string) (*corev1.Service, error) {
+	service := &corev1.Service{}
+
+	err := k8sClient.Get(ctx, getNamespacedName(podName, namespace), service)
+
+	switch {
+	case err == nil:
+		return service, nil
+	case apierrors.IsNotFound(err):
+		return nil, nil
+	case !apierrors.IsForbidden(err):
+		return nil, err
+	}
+
+	logger.Info("Failed to get pod service, listing services", "service", podName, "error", err.Error())
+
+	serviceList := &corev1.ServiceList{}
+	if err := k8sClient.List(ctx, serviceList, &client.ListOptions{Namespace: namespace}); err != nil {
+		return nil, err
+	}
+
+	for idx := range serviceList.Items {
+		if serviceList.Items[idx].Name == podName {
+			return &serviceList.Items[idx], nil
 		}
 	}
 
-	return servicePort, serviceTLSPort, adminPort, adminTLSPort, err
+	return nil, nil
 }
 
 func (initp *InitParams) isNodeNetwork() bool {
 	networkSet := sets.NewString(
+		string(initp.networkInfo.networkPolicy.AccessType),
+		string(initp.networkInfo.networkPolicy.TLSAccessType),
+		string(initp.networkInfo.networkPolicy.AlternateAccessType),
+		string(initp.networkInfo.networkPolicy.TLSAlternateAccessType),
+	)
+
+	// Network types other than these ones advertise the node addresses and ports.
+	podNetworkSet := sets.NewString(
 		string(asdbv1.AerospikeNetworkTypePod),
 		string(asdbv1.AerospikeNetworkTypeCustomInterface),
-		string(initp.aeroCluster.Spec.AerospikeNetworkPolicy.AccessType),
-		string(initp.aeroCluster.Spec.AerospikeNetworkPolicy.TLSAccessType),
-		string(initp.aeroCluster.Spec.AerospikeNetworkPolicy.AlternateAccessType),
-		string(initp.aeroCluster.Spec.AerospikeNetworkPolicy.TLSAlternateAccessType),
+		string(networkTypeLoadBalancer),
+		string(networkTypeDNSName),
 	)
 
-	// If len of set is more than 2, it means network type different from "pod" and  "customInterface" are present.
-	return networkSet.Len() > 2
+	return networkSet.Difference(podNetworkSet).Len() > 0
 }
 
+// getHostIPS returns the internal and external addresses of each IP family of the pod's node.
 // Note: the IPs returned from here should match the IPs used in the node summary.
-func getHostIPS(ctx context.Context, k8sClient client.Client, hostIP string) (
-	internalIP, externalIP, configuredAccessIP, configuredAlternateAccessIP string, err error) {
-	internalIP = hostIP
-	externalIP = hostIP
-	nodeList := &corev1.NodeList{}
+func getHostIPS(ctx context.Context, logger logr.Logger, k8sClient client.Client, nodeName, hostIP string) (
+	internalIPs, externalIPs ipFamilies, configuredAccessIP, configuredAlternateAccessIP string, err error) {
+	node, err := getHostNode(ctx, logger, k8sClient, nodeName, hostIP)
+	if err != nil || node == nil {
+		return internalIPs, externalIPs, configuredAccessIP, configuredAlternateAccessIP, err
+	}
+
+	for _, add := range node.Status.Addresses {
+		switch add.Type { //nolint:exhaustive // only IP addresses are used
+		case corev1.NodeInternalIP:
+			internalIPs.set(add.Address)
+		case corev1.NodeExternalIP:
+			externalIPs.set(add.Address)
+		}
+	}
 
-	// Get External IP
-	if err := k8sClient.List(ctx, nodeList); err != nil {
-		return internalIP, externalIP, configuredAccessIP, configuredAlternateAccessIP, err
+	if ip, exists := node.Labels[configuredAccessIPLabel]; exists {
+		configuredAccessIP = ip
 	}
 
-	for idx := range nodeList.Items {
-		node := &nodeList.Items[idx]
-		nodeInternalIP := ""
-		nodeExternalIP := ""
-		matchFound := false
+	if ip, exists := node.Labels[configuredAlternateAccessIPLabel]; exists {
+		configuredAlternateAccessIP = ip
+	}
 
-		for _, add := range node.Status.Addresses {
-			if add.Address == hostIP {
-				matchFound = true
-			}
+	return internalIPs, externalIPs, configuredAccessIP, configuredAlternateAccessIP, nil
+}
 
-			if add.Type == corev1.NodeInternalIP && net.ParseIP(add.Address).To4() != nil {
-				nodeInternalIP = add.Address
-			} else if add.Type == corev1.NodeExternalIP && net.ParseIP(add.Address).To4() != nil {
-				nodeExternalIP = add.Address
-			}
+// getHostNode returns the node the pod is scheduled on, nil if it is not found.
+// The nodes are listed and matched by host IP only if the node name is unknown or the node cannot be read.
+func getHostNode(ctx context.Context, logger logr.Logger, k8sClient client.Client, nodeName,
+	hostIP string) (*corev1.Node, error) {
+	if nodeName != "" {
+		node := &corev1.Node{}
+
+		err := k8sClient.Get(ctx, types.NamespacedName{Name: nodeName}, node)
+		if err == nil {
+			return node, nil
 		}
 
-		if matchFound {
-			if nodeInternalIP != "" {
-				internalIP = nodeInternalIP
-			}
+		if !apierrors.IsForbidden(err) && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
 
-			if nodeExternalIP != "" {
-				externalIP = nodeExternalIP
-			}
+		logger.Info("Failed to get pod node, listing nodes", "node", nodeName, "error", err.Error())
+	}
 
-			if ip, exists := node.Labels[configuredAccessIPLabel]; exists {
-				configuredAccessIP = ip
-			}
+	nodeList := &corev1.NodeList{}
+	if err := k8sClient.List(ctx, nodeList); err != nil {
+		return nil, err
+	}
 
-			if ip, exists := node.Labels[configuredAlternateAccessIPLabel]; exists {
-				configuredAlternateAccessIP = ip
-			}
+	for idx := range nodeList.Items {
+		node := &nodeList.Items[idx]
 
-			break
+		for _, add := range node.Status.Addresses {
+			if add.Address == hostIP {
+				return node, nil
+			}
 		}
 	}
 
-	return internalIP, externalIP, configuredAccessIP, configuredAlternateAccessIP, nil
+	return nil, nil
 }
 
 // parseCustomNetworkIP function parses the network IPs for the given list of network names
@@ -493,6 +636,7 @@ func parseCustomNetworkIP(networkType asdbv1.AerospikeNetworkType,
 	}
 
 	networkSet := sets.NewString(networks...)
+	foundNetworks := sets.NewString()
 
 	for idx := range netStatuses {
 		network := &netStatuses[idx]
@@ -503,12 +647,14 @@ func parseCustomNetworkIP(networkType asdbv1.AerospikeNetworkType,
 			}
 
 			networkIPs = append(networkIPs, network.IPs...)
+			foundNetworks.Insert(network.Name)
 		}
 	}
 
-	if len(networkIPs) == 0 {
+	// The CNI meta-plugin may list the networks one by one, wait until all of them are listed.
+	if missing := networkSet.Difference(foundNetworks); missing.Len() != 0 || len(networkIPs) == 0 {
 		return networkIPs, fmt.Errorf("networks %+v not found in pod annotations key %s",
-			networks, networkStatusAnnotation)
+			missing.List(), networkStatusAnnotation)
 	}
 
 	return networkIPs, nil
@@ -0,0 +1,107 @@
+package pkg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	asdbv1 "github.com/aerospike/aerospike-kubernetes-operator/v4/api/v1"
+)
+
+func TestParseCustomNetworkIP(t *testing.T) {
+	const (
+		bothNetworks = `[{"name":"default/net1","ips":["10.1.0.1"]},{"name":"default/net2","ips":["10.2.0.1","fd02::1"]}]`
+		oneNetwork   = `[{"name":"cbr0","ips":["10.0.0.1"]},{"name":"default/net1","ips":["10.1.0.1"]}]`
+		noIPs        = `[{"name":"default/net1","ips":["10.1.0.1"]},{"name":"default/net2","ips":[]}]`
+	)
+
+	tests := []struct {
+		name        string
+		networkType asdbv1.AerospikeNetworkType
+		// status is the network status annotation, not set if empty.
+		status   string
+		networks []string
+		want     []string
+		wantErr  string
+	}{
+		{
+			name:        "not a custom interface",
+			networkType: asdbv1.AerospikeNetworkTypePod,
+			networks:    []string{"default/net1"},
+		},
+		{
+			name:        "all networks",
+			networkType: asdbv1.AerospikeNetworkTypeCustomInterface,
+			status:      bothNetworks,
+			networks:    []string{"default/net1", "default/net2"},
+			want:        []string{"10.1.0.1", "10.2.0.1", "fd02::1"},
+		},
+		{
+			name:        "subset of the networks",
+			networkType: asdbv1.AerospikeNetworkTypeCustomInterface,
+			status:      bothNetworks,
+			networks:    []string{"default/net2"},
+			want:        []string{"10.2.0.1", "fd02::1"},
+		},
+		{
+			name:        "network not listed yet",
+			networkType: asdbv1.AerospikeNetworkTypeCustomInterface,
+			status:      oneNetwork,
+			networks:    []string{"default/net1", "default/net2"},
+			wantErr:     "networks [default/net2] not found",
+		},
+		{
+			name:        "no network listed",
+			networkType: asdbv1.AerospikeNetworkTypeCustomInterface,
+			status:      oneNetwork,
+			networks:    []string{"default/net2", "default/net3"},
+			wantErr:     "networks [default/net2 default/net3] not found",
+		},
+		{
+			name:        "network without IPs",
+			networkType: asdbv1.AerospikeNetworkTypeCustomInterface,
+			status:      noIPs,
+			networks:    []string{"default/net1", "default/net2"},
+			wantErr:     "ips list empty for network default/net2",
+		},
+		{
+			name:        "no network status",
+			networkType: asdbv1.AerospikeNetworkTypeCustomInterface,
+			networks:    []string{"default/net1"},
+			wantErr:     "annotation key " + networkStatusAnnotation + " is missing",
+		},
+		{
+			name:        "invalid network status",
+			networkType: asdbv1.AerospikeNetworkTypeCustomInterface,
+			status:      "[",
+			networks:    []string{"default/net1"},
+			wantErr:     "json unmarshal failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations := map[string]string{}
+			if tt.status != "" {
+				annotations[networkStatusAnnotation] = tt.status
+			}
+
+			got, err := parseCustomNetworkIP(tt.networkType, annotations, tt.networks)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseCustomNetworkIP() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseCustomNetworkIP() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCustomNetworkIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	wipeProgressAnnotation     = "aerospike.com/volume-wipe-progress"
+	volumeCheckpointAnnotation = "aerospike.com/volume-init-checkpoint"
+	wipeProgressInterval       = time.Minute
+)
+
+// volumeWipeStatus is the progress of a single volume wipe as published in the pod annotation.
+type volumeWipeStatus struct {
+	Method         string  `json:"method"`
+	ETA            string  `json:"eta,omitempty"`
+	BytesWritten   int64   `json:"bytesWritten"`
+	TotalBytes     int64   `json:"totalBytes"`
+	BytesPerSecond int64   `json:"bytesPerSecond"`
+	Percent        float64 `json:"percent"`
+	Finished       bool    `json:"finished"`
+}
+
+// volumeCheckpoint is the journal entry of a volume initialization, persisted in the pod annotation.
+// A restarted init resumes the initialization from offset if the PVC and method are unchanged.
+type volumeCheckpoint struct {
+	PVCUID string `json:"pvcUID"`
+	Method string `json:"method"`
+	Offset int64  `json:"offset"`
+}
+
+type trackedWipe struct {
+	start  time.Time
+	zeroer *deviceZeroer
+	method string
+	pvcUID string
+}
+
+// wipeProgress periodically publishes the progress of the volume wipes on the pod,
+// as an annotation and as k8s Events. It also journals the offset reached by volume
+// initializations so that they can be resumed after an init container restart.
+type wipeProgress struct {
+	initp       *InitParams
+	volumes     map[string]*trackedWipe
+	checkpoints map[string]volumeCheckpoint
+	stopCh      chan struct{}
+	doneCh      chan struct{}
+	mu          sync.Mutex
+}
+
+func (initp *InitParams) newWipeProgress(pod *corev1.Pod) *wipeProgress {
+	checkpoints := make(map[string]volumeCheckpoint)
+
+	if data, ok := pod.Annotations[volumeCheckpointAnnotation]; ok {
+		if err := json.Unmarshal([]byte(data), &checkpoints); err != nil {
+			initp.logger.Error(err, "Ignoring invalid volume init checkpoint", "annotation", volumeCheckpointAnnotation)
+
+			checkpoints = make(map[string]volumeCheckpoint)
+		}
+	}
+
+	return &wipeProgress{
+		initp:       initp,
+		volumes:     make(map[string]*trackedWipe),
+		checkpoints: checkpoints,
+		stopCh:      make(chan struct{}),
+		doneCh:      make(chan struct{}),
+	}
+}
+
+// resumeOffset returns the offset to resume a volume initialization from, 0 to start over.
+func (p *wipeProgress) resumeOffset(volumeName, pvcUID, method string) int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	checkpoint, ok := p.checkpoints[volumeName]
+	if !ok || pvcUID == "" || checkpoint.PVCUID != pvcUID || checkpoint.Method != method {
+		return 0
+	}
+
+	p.initp.logger.Info("Resuming volume initialization from checkpoint", "volume", volumeName,
+		"pvc-uid", pvcUID, "method", method, "offset", checkpoint.Offset)
+
+	return checkpoint.Offset
+}
+
+// track adds a volume wipe to be reported. A volume initialization is journaled if pvcUID is set.
+func (p *wipeProgress) track(volumeName, method, pvcUID string, zeroer *deviceZeroer) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.volumes[volumeName] = &trackedWipe{
+		start:  time.Now(),
+		zeroer: zeroer,
+		method: method,
+		pvcUID: pvcUID,
+	}
+}
+
+// updateCheckpoints records the durable offset of the tracked volume initializations.
+func (p *wipeProgress) updateCheckpoints() (map[string]volumeCheckpoint, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	changed := false
+
+	for name, wipe := range p.volumes {
+		if wipe.pvcUID == "" {
+			continue
+		}
+
+		checkpoint := volumeCheckpoint{
+			PVCUID: wipe.pvcUID,
+			Method: wipe.method,
+			Offset: wipe.zeroer.bytesSynced(),
+		}
+
+		if p.checkpoints[name] != checkpoint {
+			p.checkpoints[name] = checkpoint
+			changed = true
+		}
+	}
+
+	checkpoints := make(map[string]volumeCheckpoint, len(p.checkpoints))
+	for name, checkpoint := range p.checkpoints {
+		checkpoints[name] = checkpoint
+	}
+
+	return checkpoints, changed
+}
+
+// start publishes the progress every wipeProgressInterval until the returned stop function is called.
+// Stop publishes the final progress.
+func (p *wipeProgress) start(ctx context.Context) (stop func()) {
+	go func() {
+		defer close(p.doneCh)
+
+		ticker := time.NewTicker(wipeProgressInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				p.report(ctx, false)
+			case <-p.stopCh:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(p.stopCh)
+		<-p.doneCh
+		p.report(ctx, true)
+	}
+}
+
+func (p *wipeProgress) snapshot() map[string]volumeWipeStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	statuses := make(map[string]volumeWipeStatus, len(p.volumes))
+
+	for name, wipe := range p.volumes {
+		written := wipe.zeroer.bytesWritten()
+		total := wipe.zeroer.totalBytes()
+		status := volumeWipeStatus{
+			Method:       wipe.method,
+			BytesWritten: written,
+			TotalBytes:   total,
+			Finished:     wipe.zeroer.finished(),
+		}
+
+		if elapsed := time.Since(wipe.start).Seconds(); elapsed > 0 {
+			status.BytesPerSecond = int64(float64(written-wipe.zeroer.startOffset) / elapsed)
+		}
+
+		if total > 0 {
+			status.Percent = float64(written*1000/total) / 10
+		}
+
+		if !status.Finished && total > 0 && status.BytesPerSecond > 0 {
+			eta := time.Duration(float64(total-written)/float64(status.BytesPerSecond)) * time.Second
+			status.ETA = eta.Round(time.Second).String()
+		}
+
+		statuses[name] = status
+	}
+
+	return statuses
+}
+
+func (p *wipeProgress) report(ctx context.Context, final bool) {
+	if checkpoints, changed := p.updateCheckpoints(); changed {
+		if err := p.initp.patchPodAnnotation(ctx, volumeCheckpointAnnotation, checkpoints); err != nil {
+			p.initp.logger.Error(err, "Failed to persist volume init checkpoint")
+		}
+	}
+
+	statuses := p.snapshot()
+	if len(statuses) == 0 {
+		return
+	}
+
+	if err := p.initp.patchPodAnnotation(ctx, wipeProgressAnnotation, statuses); err != nil {
+		p.initp.logger.Error(err, "Failed to publish volume wipe progress")
+	}
+
+	names := make([]string, 0, len(statuses))
+	for name := range statuses {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		status := statuses[name]
+		eventType := corev1.EventTypeNormal
+		reason := reasonVolumeWipeProgress
+
+		// The final report also runs after a failed or cancelled wipe, only finished volumes are completed.
+		switch {
+		case final && status.Finished:
+			reason = reasonVolumeWipeCompleted
+		case final:
+			eventType = corev1.EventTypeWarning
+			reason = reasonVolumeWipeInterrupted
+		case status.Finished:
+			continue
+		}
+
+		message := fmt.Sprintf("Volume %s %s: %.1f%% done, %s/s", name, status.Method, status.Percent,
+			resource.NewQuantity(status.BytesPerSecond, resource.BinarySI).String())
+		if status.ETA != "" {
+			message += ", ETA " + status.ETA
+		}
+
+		p.initp.logger.Info(message)
+		p.initp.recorder.podEvent(ctx, eventType, reason, message)
+	}
+}
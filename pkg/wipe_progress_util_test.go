@@ -0,0 +1,88 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWipeProgressResumeOffset(t *testing.T) {
+	initp := &InitParams{logger: logr.Discard()}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				volumeCheckpointAnnotation: `{"data":{"pvcUID":"uid-1","method":"dd","offset":1048576}}`,
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		pod        *corev1.Pod
+		volumeName string
+		pvcUID     string
+		method     string
+		want       int64
+	}{
+		{name: "matching checkpoint", pod: pod, volumeName: "data", pvcUID: "uid-1", method: "dd", want: 1048576},
+		{name: "other volume", pod: pod, volumeName: "logs", pvcUID: "uid-1", method: "dd"},
+		{name: "changed PVC", pod: pod, volumeName: "data", pvcUID: "uid-2", method: "dd"},
+		{name: "changed method", pod: pod, volumeName: "data", pvcUID: "uid-1", method: "headerCleanup"},
+		{name: "no PVC UID", pod: pod, volumeName: "data", method: "dd"},
+		{name: "no checkpoint", pod: &corev1.Pod{}, volumeName: "data", pvcUID: "uid-1", method: "dd"},
+		{
+			name: "invalid checkpoint",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{volumeCheckpointAnnotation: "{"},
+				},
+			},
+			volumeName: "data", pvcUID: "uid-1", method: "dd",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress := initp.newWipeProgress(tt.pod)
+
+			if got := progress.resumeOffset(tt.volumeName, tt.pvcUID, tt.method); got != tt.want {
+				t.Errorf("resumeOffset() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWipeProgressUpdateCheckpoints(t *testing.T) {
+	initp := &InitParams{logger: logr.Discard()}
+	progress := initp.newWipeProgress(&corev1.Pod{})
+
+	initZeroer := newDeviceZeroer("/dev/data", 2048, 0)
+	progress.track("data", "dd", "uid-1", initZeroer)
+	// Wipes are not journaled.
+	progress.track("logs", "dd", "", newDeviceZeroer("/dev/logs", 0, 0))
+
+	want := map[string]volumeCheckpoint{
+		"data": {PVCUID: "uid-1", Method: "dd", Offset: 2048},
+	}
+
+	checkpoints, changed := progress.updateCheckpoints()
+	if !changed || !reflect.DeepEqual(checkpoints, want) {
+		t.Fatalf("updateCheckpoints() = %v, %v, want %v, true", checkpoints, changed, want)
+	}
+
+	if _, changed := progress.updateCheckpoints(); changed {
+		t.Error("updateCheckpoints() without progress reported a change")
+	}
+
+	initZeroer.synced.Store(4096)
+	want["data"] = volumeCheckpoint{PVCUID: "uid-1", Method: "dd", Offset: 4096}
+
+	checkpoints, changed = progress.updateCheckpoints()
+	if !changed || !reflect.DeepEqual(checkpoints, want) {
+		t.Errorf("updateCheckpoints() = %v, %v, want %v, true", checkpoints, changed, want)
+	}
+}
@@ -0,0 +1,177 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+	"syscall"
+)
+
+const (
+	// zeroBlockSize is the size of a single write, same as the block size used with dd.
+	zeroBlockSize = 1024 * 1024
+	// headerCleanupSize is the length zeroed at the start of a device for header cleanup.
+	headerCleanupSize = 8 * zeroBlockSize
+	// zeroSyncInterval is the number of bytes written between two syncs of the device.
+	// Data up to the last sync is durable and a restarted init can resume from there.
+	zeroSyncInterval = 1024 * zeroBlockSize
+)
+
+// zeroDeviceError is returned when a device could not be zeroed completely.
+type zeroDeviceError struct {
+	err    error
+	device string
+	offset int64
+}
+
+func (e *zeroDeviceError) Error() string {
+	return fmt.Sprintf("failed to zero device %s at offset %d: %v", e.device, e.offset, e.err)
+}
+
+func (e *zeroDeviceError) Unwrap() error {
+	return e.err
+}
+
+// deviceZeroer writes zeros to a block device or file in-process.
+// The number of bytes written and to be written are updated as the write progresses and can be read concurrently.
+type deviceZeroer struct {
+	device string
+	// startOffset is the offset to start zeroing from, when resuming an earlier run.
+	startOffset int64
+	// length is the number of bytes to zero from the start of the device, 0 means the complete device.
+	length  int64
+	written atomic.Int64
+	synced  atomic.Int64
+	total   atomic.Int64
+	done    atomic.Bool
+}
+
+func newDeviceZeroer(device string, startOffset, length int64) *deviceZeroer {
+	z := &deviceZeroer{
+		device:      device,
+		startOffset: startOffset,
+		length:      length,
+	}
+
+	z.written.Store(startOffset)
+	z.synced.Store(startOffset)
+
+	return z
+}
+
+// bytesWritten returns the offset zeroed so far.
+func (z *deviceZeroer) bytesWritten() int64 {
+	return z.written.Load()
+}
+
+// bytesSynced returns the offset up to which the zeroes are durable on the device.
+func (z *deviceZeroer) bytesSynced() int64 {
+	return z.synced.Load()
+}
+
+// totalBytes returns the number of bytes to be zeroed, 0 if not known yet.
+func (z *deviceZeroer) totalBytes() int64 {
+	return z.total.Load()
+}
+
+// finished returns true once the device is zeroed completely.
+func (z *deviceZeroer) finished() bool {
+	return z.done.Load()
+}
+
+// run zeroes the device. The device size is looked up before writing,
+// so that the write stops cleanly at the end of the device.
+func (z *deviceZeroer) run(ctx context.Context) error {
+	f, err := os.OpenFile(z.device, os.O_WRONLY, 0)
+	if err != nil {
+		return &zeroDeviceError{device: z.device, err: err}
+	}
+
+	defer f.Close()
+
+	size, err := deviceSize(f)
+	if err != nil {
+		return &zeroDeviceError{device: z.device, err: err}
+	}
+
+	limit := size
+	if z.length > 0 && (limit == 0 || z.length < limit) {
+		limit = z.length
+	}
+
+	z.total.Store(limit)
+
+	offset := z.startOffset
+	if limit != 0 && offset > limit {
+		offset = limit
+
+		z.written.Store(offset)
+		z.synced.Store(offset)
+	}
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return &zeroDeviceError{device: z.device, offset: offset, err: err}
+	}
+
+	buf := make([]byte, zeroBlockSize)
+	lastSync := offset
+
+	for limit == 0 || offset < limit {
+		if err := ctx.Err(); err != nil {
+			return &zeroDeviceError{device: z.device, offset: offset, err: err}
+		}
+
+		chunk := buf
+		if limit != 0 && limit-offset < int64(len(chunk)) {
+			chunk = chunk[:limit-offset]
+		}
+
+		n, err := f.Write(chunk)
+		offset += int64(n)
+		z.written.Store(offset)
+
+		if err != nil {
+			// Size could not be determined upfront, end of device is reached.
+			if size == 0 && errors.Is(err, syscall.ENOSPC) {
+				break
+			}
+
+			return &zeroDeviceError{device: z.device, offset: offset, err: err}
+		}
+
+		if offset-lastSync >= zeroSyncInterval {
+			if err := f.Sync(); err != nil {
+				return &zeroDeviceError{device: z.device, offset: offset, err: err}
+			}
+
+			lastSync = offset
+			z.synced.Store(offset)
+		}
+	}
+
+	if err := f.Sync(); err != nil {
+		return &zeroDeviceError{device: z.device, offset: offset, err: err}
+	}
+
+	z.synced.Store(offset)
+	z.done.Store(true)
+
+	return nil
+}
+
+// deviceSize returns the size of a block device or regular file.
+func deviceSize(f *os.File) (int64, error) {
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
@@ -0,0 +1,122 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestDevice writes a file of the given size filled with 0xff bytes and returns its path.
+func writeTestDevice(t *testing.T, size int) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "device")
+	if err := os.WriteFile(path, bytes.Repeat([]byte{0xff}, size), 0600); err != nil {
+		t.Fatalf("failed to write test device: %v", err)
+	}
+
+	return path
+}
+
+func TestDeviceZeroer(t *testing.T) {
+	const size = 3*zeroBlockSize + 123
+
+	tests := []struct {
+		name        string
+		startOffset int64
+		length      int64
+		// zeroFrom and zeroTo delimit the bytes expected to be zeroed.
+		zeroFrom int64
+		zeroTo   int64
+	}{
+		{name: "complete device", zeroTo: size},
+		{name: "header cleanup", length: zeroBlockSize + 10, zeroTo: zeroBlockSize + 10},
+		{name: "length beyond device", length: 2 * size, zeroTo: size},
+		{name: "resume from offset", startOffset: zeroBlockSize, zeroFrom: zeroBlockSize, zeroTo: size},
+		{name: "resume beyond length", startOffset: size + 1, length: 10, zeroFrom: 10, zeroTo: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			device := writeTestDevice(t, size)
+			zeroer := newDeviceZeroer(device, tt.startOffset, tt.length)
+
+			if err := zeroer.run(context.Background()); err != nil {
+				t.Fatalf("run() error = %v", err)
+			}
+
+			if !zeroer.finished() {
+				t.Error("finished() = false after run")
+			}
+
+			if zeroer.bytesWritten() != tt.zeroTo || zeroer.bytesSynced() != tt.zeroTo {
+				t.Errorf("bytesWritten() = %d, bytesSynced() = %d, want %d", zeroer.bytesWritten(),
+					zeroer.bytesSynced(), tt.zeroTo)
+			}
+
+			data, err := os.ReadFile(device)
+			if err != nil {
+				t.Fatalf("failed to read test device: %v", err)
+			}
+
+			if len(data) != size {
+				t.Fatalf("device size changed to %d, want %d", len(data), size)
+			}
+
+			for offset, b := range data {
+				zeroed := int64(offset) >= tt.zeroFrom && int64(offset) < tt.zeroTo
+				if (b == 0) != zeroed {
+					t.Fatalf("byte at offset %d = %#x, want zeroed %v", offset, b, zeroed)
+				}
+			}
+		})
+	}
+}
+
+func TestDeviceZeroerErrors(t *testing.T) {
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		device  string
+		wantErr error
+	}{
+		{
+			name:    "cancelled",
+			ctx:     cancelledCtx,
+			device:  writeTestDevice(t, zeroBlockSize),
+			wantErr: context.Canceled,
+		},
+		{
+			name:    "missing device",
+			ctx:     context.Background(),
+			device:  filepath.Join(t.TempDir(), "missing"),
+			wantErr: os.ErrNotExist,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zeroer := newDeviceZeroer(tt.device, 0, 0)
+
+			err := zeroer.run(tt.ctx)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("run() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var zeroErr *zeroDeviceError
+			if !errors.As(err, &zeroErr) || zeroErr.device != tt.device {
+				t.Errorf("run() error = %#v, want a zeroDeviceError for %s", err, tt.device)
+			}
+
+			if zeroer.finished() {
+				t.Error("finished() = true after a failed run")
+			}
+		})
+	}
+}